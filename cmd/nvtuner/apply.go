@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"nvtuner-go/internal/config"
+	"nvtuner-go/internal/gpu"
+	"nvtuner-go/internal/tuning"
+)
+
+func runApply(args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	cfgPath := fs.String("config", config.DefaultFileName, "config file")
+	dryRun := fs.Bool("dry-run", false, "show the plan without touching hardware")
+	index := fs.Int("gpu", -1, "only this GPU index (default all)")
+	modeFlag := fs.String("mode", "", "all-or-nothing or best-effort (default from config)")
+	fs.Parse(args)
+
+	cfg := config.New(*cfgPath)
+	if err := cfg.Load(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	drv, err := openDriver()
+	if err != nil {
+		return err
+	}
+	defer drv.Shutdown()
+
+	devs, states, err := fetchStates(drv)
+	if err != nil {
+		return err
+	}
+
+	modeStr := *modeFlag
+	if modeStr == "" {
+		modeStr = cfg.ApplyMode
+	}
+	mode, err := tuning.ParseMode(modeStr)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for i, d := range states {
+		if *index >= 0 && d.Index != *index {
+			continue
+		}
+		s, src, ok := cfg.Resolve(config.IdentityOf(d))
+		if !ok {
+			fmt.Printf("GPU %d %s: not configured, skipped\n\n", d.Index, d.Name)
+			continue
+		}
+
+		plan := tuning.PlanApply(d.Params, devs[i], d, s)
+		fmt.Printf("GPU %d %s (%s)\n", d.Index, d.Name, src)
+		printPlan(os.Stdout, plan)
+		if *dryRun {
+			fmt.Println()
+			continue
+		}
+
+		res := tuning.Execute(plan, devs[i], mode)
+		for _, r := range res.Steps {
+			if r.Err != nil {
+				fmt.Printf("  %s: %v\n", r.ID, r.Err)
+				if hint := gpu.Hint(r.Err); hint != "" {
+					fmt.Printf("    hint: %s\n", hint)
+				}
+			}
+			if r.RollbackErr != nil {
+				fmt.Printf("  %s: rollback failed: %v\n", r.ID, r.RollbackErr)
+			}
+		}
+		fmt.Printf("  %s\n\n", res)
+		failed += len(res.Failed())
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d parameter(s) failed to apply", failed)
+	}
+	return nil
+}
+
+func printPlan(out io.Writer, plan tuning.Plan) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  PARAM\tCURRENT\tTARGET\tUNIT\tSTATUS")
+	for _, st := range plan.Steps {
+		status := "change"
+		switch {
+		case st.WillFail:
+			status = "will fail: " + st.Reason
+		case st.NoOp:
+			status = "no-op"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n",
+			st.Param.ID, st.Param.FormatValue(st.Current), st.Param.FormatValue(st.Target), st.Param.DisplayUnit(), status)
+	}
+	w.Flush()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"nvtuner-go/internal/gpu"
+)
+
+func runClocks(args []string) error {
+	fs := flag.NewFlagSet("clocks", flag.ExitOnError)
+	index := fs.Int("gpu", -1, "only show this GPU index")
+	asJSON := fs.Bool("json", false, "print as json")
+	fs.Parse(args)
+
+	drv, err := openDriver()
+	if err != nil {
+		return err
+	}
+	defer drv.Shutdown()
+
+	_, states, err := fetchStates(drv)
+	if err != nil {
+		return err
+	}
+	if *index >= 0 {
+		if *index >= len(states) {
+			return fmt.Errorf("no GPU with index %d", *index)
+		}
+		states = states[*index : *index+1]
+	}
+
+	if *asJSON {
+		type entry struct {
+			Index  int            `json:"index"`
+			UUID   string         `json:"uuid"`
+			Clocks gpu.ClockTable `json:"clocks"`
+		}
+		out := make([]entry, len(states))
+		for i, d := range states {
+			out[i] = entry{d.Index, d.UUID, d.Clocks}
+		}
+		return printJSON(out)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, d := range states {
+		fmt.Fprintf(w, "GPU %d: %s\n", d.Index, d.Name)
+		if len(d.Clocks.Mem) == 0 {
+			fmt.Fprintln(w, "  no clock table")
+			continue
+		}
+		appMem, _ := d.Param("app_mem")
+		fmt.Fprintln(w, "  MEM\tGPU")
+		for i := len(d.Clocks.Mem) - 1; i >= 0; i-- {
+			mclk := d.Clocks.Mem[i]
+			fmt.Fprintf(w, "  %s\t%s\n", clockCell(mclk, d.Clocks.Mem, d.ClockMem, appMem.Default, d.Setting("app_mem"), d.Setting("mem_cl_min"), d.Setting("mem_cl")),
+				clockRow(d.Clocks.Gpu[mclk], mclk, d))
+		}
+	}
+	fmt.Fprintln(w, "\n* current  d app default  A app clock  L within clock lock")
+	return w.Flush()
+}
+
+// clockRow prints the graphics clocks allowed at mclk, fastest first.
+func clockRow(clks []int, mclk int, d gpu.DState) string {
+	cur := gpu.UnitMHz.NA(nil)
+	if d.ClockMem.Valid && gpu.SnapDown(d.Clocks.Mem, d.ClockMem.V) == mclk {
+		cur = d.ClockGpu
+	}
+	appGpu, _ := d.Param("app_gpu")
+	cells := make([]string, len(clks))
+	for i := len(clks) - 1; i >= 0; i-- {
+		cells[len(clks)-1-i] = clockCell(clks[i], clks, cur, appGpu.Default, d.Setting("app_gpu"), d.Setting("gpu_cl_min"), d.Setting("gpu_cl"))
+	}
+	return strings.Join(cells, " ")
+}
+
+// clockCell prints a table clock followed by its markers. The current clock
+// is matched to the table entry at or below it.
+func clockCell(v int, table []int, cur, appDef, app, lockMin, lockMax gpu.Value) string {
+	is := func(m gpu.Value) bool { return m.Valid && m.V == v }
+	marks := ""
+	if cur.Valid && gpu.SnapDown(table, cur.V) == v {
+		marks += "*"
+	}
+	if is(appDef) {
+		marks += "d"
+	}
+	if is(app) {
+		marks += "A"
+	}
+	if lockMin.Valid && lockMax.Valid && v >= lockMin.V && v <= lockMax.V {
+		marks += "L"
+	}
+	return fmt.Sprintf("%d%s", v, marks)
+}
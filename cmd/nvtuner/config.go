@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"nvtuner-go/internal/config"
+	"nvtuner-go/internal/gpu"
+)
+
+func runConfig(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: nvtuner config <match|check> [flags]")
+	}
+
+	switch args[0] {
+	case "match":
+		return runConfigMatch(args[1:])
+	case "check":
+		return runConfigCheck(args[1:])
+	default:
+		return fmt.Errorf("unknown config command %q", args[0])
+	}
+}
+
+func runConfigMatch(args []string) error {
+	fs := flag.NewFlagSet("config match", flag.ExitOnError)
+	cfgPath := fs.String("config", config.DefaultFileName, "config file")
+	fs.Parse(args)
+
+	cfg := config.New(*cfgPath)
+	if err := cfg.Load(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	drv, err := openDriver()
+	if err != nil {
+		return err
+	}
+	defer drv.Shutdown()
+
+	_, states, err := fetchStates(drv)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "IDX\tNAME\tPCI\tSUBSYS\tBOARD\tSOURCE\tPL\tGPU_CO\tMEM_CO\tGPU_CL\tMEM_CL")
+	for _, d := range states {
+		id := config.IdentityOf(d)
+		pci := fmt.Sprintf("%04X:%04X", id.PciDevice&0xFFFF, id.PciDevice>>16) // vendor:device, like lspci
+		s, src, ok := cfg.Resolve(id)
+		if !ok {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%08X\t%s\tnone\t-\t-\t-\t-\t-\n",
+				d.Index, d.Name, pci, id.PciSubsys, id.Board)
+			continue
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%08X\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			d.Index, d.Name, pci, id.PciSubsys, id.Board, src, gpu.FormatWatts(s.Get("pl")), s.Get("gpu_co"), s.Get("mem_co"),
+			lockRange(s.Get("gpu_cl_min"), s.Get("gpu_cl")), lockRange(s.Get("mem_cl_min"), s.Get("mem_cl")))
+	}
+	return w.Flush()
+}
+
+func runConfigCheck(args []string) error {
+	fs := flag.NewFlagSet("config check", flag.ExitOnError)
+	cfgPath := fs.String("config", config.DefaultFileName, "config file")
+	fix := fs.Bool("fix", false, "clamp out-of-range values and prune absent GPUs")
+	fs.Parse(args)
+
+	cfg := config.New(*cfgPath)
+	if err := cfg.Load(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	drv, err := openDriver()
+	if err != nil {
+		return err
+	}
+	defer drv.Shutdown()
+
+	_, states, err := fetchStates(drv)
+	if err != nil {
+		return err
+	}
+
+	issues := cfg.Check(states, *fix)
+	unfixed := 0
+	for _, i := range issues {
+		fmt.Println(i)
+		if i.Severity == config.SeverityError && !i.Fixed {
+			unfixed++
+		}
+	}
+
+	if *fix && len(issues) > 0 {
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+	if unfixed > 0 {
+		return fmt.Errorf("%d error(s) in %s", unfixed, *cfgPath)
+	}
+	if len(issues) == 0 {
+		fmt.Println("ok")
+	}
+	return nil
+}
+
+// lockRange prints a clock lock as "min-max", leaving unset sides empty.
+func lockRange(lo, hi int) string {
+	f := func(v int) string {
+		if v <= 0 {
+			return ""
+		}
+		return strconv.Itoa(v)
+	}
+	return f(lo) + "-" + f(hi)
+}
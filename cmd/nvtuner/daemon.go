@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"nvtuner-go/internal/config"
+	"nvtuner-go/internal/gpu"
+	"nvtuner-go/internal/tuning"
+)
+
+const (
+	alertTimeout = 30 * time.Second
+	reapplyTries = 3 // per drift, for steps failing in ways worth retrying
+)
+
+func runDaemon(args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	interval := fs.Duration("interval", 10*time.Second, "how often to check device health")
+	alertCmd := fs.String("alert", "", "program to run on new errors, given the GPU index and a message")
+	reapply := fs.Bool("reapply", false, "apply the config at start and again when settings drift or a GPU recovers")
+	recheck := fs.Duration("recheck", 5*time.Minute, "how often to look for settings drifted from the config, for -reapply")
+	cfgPath := fs.String("config", config.DefaultFileName, "config file, for -reapply")
+	fs.Parse(args)
+
+	log.SetFlags(log.LstdFlags)
+
+	var cfg *config.Manager
+	var mode tuning.Mode
+	if *reapply {
+		cfg = config.New(*cfgPath)
+		if err := cfg.Load(); err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		var err error
+		if mode, err = tuning.ParseMode(cfg.ApplyMode); err != nil {
+			return err
+		}
+	}
+
+	drv, err := openDriver()
+	if err != nil {
+		return err
+	}
+	defer drv.Shutdown()
+
+	devs, err := drv.Devices()
+	if err != nil {
+		return err
+	}
+
+	prev := make([]gpu.HealthReport, len(devs))
+	failing := make([]bool, len(devs)) // log a failing read once
+	for i, d := range devs {
+		prev[i], err = d.GetHealthReport()
+		if err != nil {
+			log.Printf("GPU %d: %v", d.GetIndex(), err)
+			failing[i] = true
+			continue
+		}
+		for _, p := range prev[i].Problems() {
+			log.Printf("GPU %d: %s", d.GetIndex(), p)
+		}
+	}
+	log.Printf("watching %d GPU(s) every %s", len(devs), *interval)
+
+	// events are optional: without them only the periodic checks run
+	listener, events, err := listen(drv)
+	if err != nil {
+		log.Printf("no events: %v", err)
+	}
+	defer func() {
+		if listener != nil {
+			listener.Stop()
+		}
+	}()
+
+	// reapply attempts left, non-zero while settings may have been changed
+	// under us
+	dirty := make([]int, len(devs))
+	markDirty := func(i int) {
+		if *reapply {
+			dirty[i] = reapplyTries
+		}
+	}
+	for i := range dirty {
+		markDirty(i)
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	tick := time.NewTicker(*interval)
+	defer tick.Stop()
+	// clock events only come on Kepler, so drift is also polled for
+	lastCheck := time.Now()
+
+	for {
+		select {
+		case s := <-sig:
+			log.Printf("%s, stopping", s)
+			return nil
+		case e := <-events:
+			log.Print(e)
+			if e.Kind == gpu.EventXid || e.Kind == gpu.EventEccDoubleBit {
+				alert(*alertCmd, e.Index, e.String())
+			}
+			if e.Kind == gpu.EventClock || e.Kind == gpu.EventPowerSource || e.Kind == gpu.EventXid {
+				for i, d := range devs {
+					if d.GetIndex() == e.Index {
+						markDirty(i)
+					}
+				}
+			}
+			continue
+		case <-tick.C:
+		}
+
+		if time.Since(lastCheck) >= *recheck {
+			lastCheck = time.Now()
+			for i := range devs {
+				markDirty(i)
+			}
+		}
+
+		lost := false
+		for i, d := range devs {
+			r, err := d.GetHealthReport()
+			if err != nil {
+				if !failing[i] {
+					log.Printf("GPU %d: %v", d.GetIndex(), err)
+				}
+				failing[i] = true
+				lost = lost || gpu.IsLost(err)
+				continue
+			}
+			if failing[i] {
+				markDirty(i) // it may have been reset meanwhile
+			}
+			failing[i] = false
+			for _, msg := range r.NewErrors(prev[i]) {
+				log.Printf("GPU %d: %s", d.GetIndex(), msg)
+				alert(*alertCmd, d.GetIndex(), msg)
+			}
+			prev[i] = r
+
+			// devices failing the health read above stay dirty until they recover
+			if dirty[i] > 0 {
+				dirty[i]--
+				if reapplyDevice(cfg, d, mode) {
+					dirty[i] = 0
+				}
+			}
+		}
+
+		// handles of lost devices stay dead, look the devices up again; that
+		// may re-init the driver, so the listener is not left waiting on it
+		if lost {
+			if listener != nil {
+				listener.Stop()
+			}
+			if err := rebindDevices(drv, devs); err != nil {
+				log.Printf("re-enumerating GPUs: %v", err)
+			}
+			listener, events, _ = listen(drv)
+		}
+	}
+}
+
+// listen subscribes to the driver's events; without them the listener and
+// channel are nil, which never delivers.
+func listen(drv gpu.Manager) (*gpu.Listener, <-chan gpu.Event, error) {
+	l, err := gpu.NewListener(drv)
+	if err != nil {
+		return nil, nil, err
+	}
+	events, _ := l.Subscribe(64)
+	return l, events, nil
+}
+
+// rebindDevices replaces the handles in devs by the current ones, matched
+// by UUID.
+func rebindDevices(drv gpu.Manager, devs []gpu.Device) error {
+	byUUID, _, err := gpu.Enumerate(drv)
+	if err != nil {
+		return err
+	}
+	for i, d := range devs {
+		if n, ok := byUUID[d.GetUUID()]; ok {
+			devs[i] = n
+		}
+	}
+	return nil
+}
+
+// reapplyDevice applies the config again if the device drifted from it. It
+// returns false if a step failed in a way worth retrying on the next check.
+func reapplyDevice(cfg *config.Manager, dev gpu.Device, mode tuning.Mode) bool {
+	var d gpu.DState
+	d.FetchOnce(dev)
+	s, _, ok := cfg.Resolve(config.IdentityOf(d))
+	if !ok {
+		return true
+	}
+
+	plan := tuning.PlanApply(d.Params, dev, d, s)
+	if plan.Changes() == 0 {
+		return true
+	}
+	log.Printf("GPU %d: %d setting(s) drifted from the config, applying", d.Index, plan.Changes())
+	res := tuning.Execute(plan, dev, mode)
+	done := true
+	for _, r := range res.Steps {
+		if r.Err != nil {
+			log.Printf("GPU %d: %s: %v", d.Index, r.ID, r.Err)
+			done = done && !gpu.IsRetryable(r.Err)
+		}
+	}
+	log.Printf("GPU %d: %s", d.Index, res)
+	return done
+}
+
+// alert runs the user's alert program, if any, and logs when it fails.
+func alert(cmd string, index int, msg string) {
+	if cmd == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+	defer cancel()
+	if out, err := exec.CommandContext(ctx, cmd, strconv.Itoa(index), msg).CombinedOutput(); err != nil {
+		log.Printf("alert %s failed: %v: %s", cmd, err, out)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"nvtuner-go/internal/config"
+	"nvtuner-go/internal/driver/nvidia"
+	"nvtuner-go/internal/gpu"
+	"nvtuner-go/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const usage = `usage: nvtuner [command] [flags]
+
+commands:
+  tui             interactive tuner (default)
+  status          show the current state of every GPU (--json)
+  caps            show what each GPU supports reading and setting (--json)
+  clocks          show the supported memory x graphics clock table (--json)
+  params          list the tuning parameters of each GPU with limits and values (--json)
+  apply           apply the configured settings (--dry-run to preview)
+  daemon          watch GPU health and events, alert on errors, optionally re-apply the config
+  config match    show which config entry applies to each GPU
+  config check    validate the config against the detected GPUs (--fix)
+  profile export  write a portable tuning profile (json or yaml)
+  profile import  load a profile, remapping it to the local GPUs
+`
+
+func main() {
+	log.SetFlags(0)
+
+	args := os.Args[1:]
+	cmd := "tui"
+	if len(args) > 0 && args[0][0] != '-' {
+		cmd, args = args[0], args[1:]
+	}
+
+	var err error
+	switch cmd {
+	case "tui":
+		err = runTui(args)
+	case "status":
+		err = runStatus(args)
+	case "caps":
+		err = runCaps(args)
+	case "clocks":
+		err = runClocks(args)
+	case "params":
+		err = runParams(args)
+	case "apply":
+		err = runApply(args)
+	case "daemon":
+		err = runDaemon(args)
+	case "config":
+		err = runConfig(args)
+	case "profile":
+		err = runProfile(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runTui(args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	cfgPath := fs.String("config", config.DefaultFileName, "config file")
+	fs.Parse(args)
+
+	cfg := config.New(*cfgPath)
+	if err := cfg.Load(); err != nil {
+		log.Printf("Warning: Failed to load config: %v", err)
+	}
+
+	drv, err := openDriver()
+	if err != nil {
+		return err
+	}
+	defer drv.Shutdown()
+
+	model, err := ui.New(drv, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create UI model: %w", err)
+	}
+	defer model.Close()
+
+	_, err = tea.NewProgram(model, tea.WithAltScreen()).Run()
+	return err
+}
+
+func openDriver() (gpu.Manager, error) {
+	drv, err := nvidia.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load driver: %w", err)
+	}
+	if err := drv.Init(); err != nil {
+		err = fmt.Errorf("failed to init driver: %w", err)
+		if hint := gpu.Hint(err); hint != "" {
+			err = fmt.Errorf("%w (%s)", err, hint)
+		}
+		return nil, err
+	}
+	return drv, nil
+}
+
+// fetchStates reads the current state of every device once.
+func fetchStates(drv gpu.Manager) ([]gpu.Device, []gpu.DState, error) {
+	devs, err := drv.Devices()
+	if err != nil {
+		return nil, nil, err
+	}
+	states := make([]gpu.DState, len(devs))
+	for i, d := range devs {
+		states[i].FetchOnce(d)
+	}
+	return devs, states, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"nvtuner-go/internal/config"
+	"nvtuner-go/internal/gpu"
+)
+
+func runParams(args []string) error {
+	fs := flag.NewFlagSet("params", flag.ExitOnError)
+	index := fs.Int("gpu", -1, "only show this GPU index")
+	cfgPath := fs.String("config", config.DefaultFileName, "config file")
+	asJSON := fs.Bool("json", false, "print as json")
+	fs.Parse(args)
+
+	cfg := config.New(*cfgPath)
+	if err := cfg.Load(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	drv, err := openDriver()
+	if err != nil {
+		return err
+	}
+	defer drv.Shutdown()
+
+	_, states, err := fetchStates(drv)
+	if err != nil {
+		return err
+	}
+	if *index >= 0 {
+		if *index >= len(states) {
+			return fmt.Errorf("no GPU with index %d", *index)
+		}
+		states = states[*index : *index+1]
+	}
+
+	if *asJSON {
+		type param struct {
+			gpu.ParamDef
+			Current    gpu.Value  `json:"current"`
+			Configured *int       `json:"configured"` // null without a config entry
+			Access     gpu.Access `json:"access"`
+		}
+		type entry struct {
+			Index  int     `json:"index"`
+			UUID   string  `json:"uuid"`
+			Params []param `json:"params"`
+		}
+		out := make([]entry, len(states))
+		for i, d := range states {
+			s, _, ok := cfg.Resolve(config.IdentityOf(d))
+			out[i] = entry{Index: d.Index, UUID: d.UUID}
+			for _, p := range d.Params {
+				e := param{ParamDef: p, Current: p.Current(d), Access: d.Caps.Get(p.ID).Access}
+				if ok {
+					v := s.Get(p.ID)
+					e.Configured = &v
+				}
+				out[i].Params = append(out[i].Params, e)
+			}
+		}
+		return printJSON(out)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, d := range states {
+		s, _, ok := cfg.Resolve(config.IdentityOf(d))
+		fmt.Fprintf(w, "GPU %d: %s\n", d.Index, d.Name)
+		fmt.Fprintln(w, "  ID\tKIND\tUNIT\tCURRENT\tCONFIG\tMIN\tMAX\tDEFAULT\tSTEP\tACCESS")
+		for _, p := range d.Params {
+			conf := "-"
+			if ok {
+				conf = p.Format(s.Get(p.ID))
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				p.ID, p.Kind, p.DisplayUnit(), p.FormatValue(p.Current(d)), conf,
+				p.FormatValue(p.Min), p.FormatValue(p.Max), p.FormatValue(p.Default), p.Format(p.Step),
+				d.Caps.Get(p.ID).Access)
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"nvtuner-go/internal/config"
+	"nvtuner-go/internal/gpu"
+	"nvtuner-go/internal/profile"
+)
+
+func runProfile(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: nvtuner profile <export|import> [flags]")
+	}
+
+	switch args[0] {
+	case "export":
+		return runProfileExport(args[1:])
+	case "import":
+		return runProfileImport(args[1:])
+	default:
+		return fmt.Errorf("unknown profile command %q", args[0])
+	}
+}
+
+func runProfileExport(args []string) error {
+	fs := flag.NewFlagSet("profile export", flag.ExitOnError)
+	cfgPath := fs.String("config", config.DefaultFileName, "config file")
+	out := fs.String("o", "", "output file (default stdout)")
+	format := fs.String("format", "", "json or yaml (default: from -o extension, else json)")
+	fs.Parse(args)
+
+	f := profile.FormatOf(*out)
+	if *format != "" {
+		var err error
+		if f, err = profile.ParseFormat(*format); err != nil {
+			return err
+		}
+	}
+
+	cfg := config.New(*cfgPath)
+	if err := cfg.Load(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	drv, err := openDriver()
+	if err != nil {
+		return err
+	}
+	defer drv.Shutdown()
+
+	_, states, err := fetchStates(drv)
+	if err != nil {
+		return err
+	}
+	var ms gpu.MState
+	ms.FetchOnce(drv)
+
+	data, err := profile.Export(ms, states, cfg).Marshal(f)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*out, data, 0644)
+}
+
+func runProfileImport(args []string) error {
+	fs := flag.NewFlagSet("profile import", flag.ExitOnError)
+	cfgPath := fs.String("config", config.DefaultFileName, "config file")
+	format := fs.String("format", "", "json or yaml (default: from file extension)")
+	yes := fs.Bool("y", false, "write without asking")
+	dryRun := fs.Bool("dry-run", false, "only show what would change")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: nvtuner profile import [flags] <file>")
+	}
+	path := fs.Arg(0)
+
+	f := profile.FormatOf(path)
+	if *format != "" {
+		var err error
+		if f, err = profile.ParseFormat(*format); err != nil {
+			return err
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	p, err := profile.Unmarshal(data, f)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	cfg := config.New(*cfgPath)
+	if err := cfg.Load(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	drv, err := openDriver()
+	if err != nil {
+		return err
+	}
+	defer drv.Shutdown()
+
+	_, states, err := fetchStates(drv)
+	if err != nil {
+		return err
+	}
+	var ms gpu.MState
+	ms.FetchOnce(drv)
+
+	fmt.Printf("profile from %s %s, driver %s, exported %s\n",
+		p.Manager, p.ManagerVersion, p.DriverVersion, p.Exported.Format("2006-01-02 15:04"))
+	if p.DriverVersion != ms.DriverVersion {
+		fmt.Printf("note: local driver is %s\n", ms.DriverVersion)
+	}
+
+	mappings := p.Remap(states, cfg)
+	if len(mappings) == 0 {
+		return fmt.Errorf("no local GPU matches any device in %s", path)
+	}
+
+	changes := 0
+	for _, m := range mappings {
+		fmt.Printf("\nGPU %d %s <- %s (by %s)\n", m.Local.Index, m.Local.Name, m.Entry.Model, m.By)
+		for _, w := range m.Warnings {
+			fmt.Printf("  warning: %s\n", w)
+		}
+		diff := m.Diff()
+		if len(diff) == 0 {
+			fmt.Println("  no changes")
+		}
+		for _, l := range diff {
+			fmt.Println("  " + l)
+		}
+		changes += len(diff)
+	}
+
+	if changes == 0 || *dryRun {
+		return nil
+	}
+	if !*yes && !confirm("\nWrite to "+*cfgPath+"?") {
+		fmt.Println("aborted")
+		return nil
+	}
+
+	for _, m := range mappings {
+		cfg.Set(m.Local.UUID, m.Entry.Settings)
+	}
+	return cfg.Save()
+}
+
+func confirm(prompt string) bool {
+	fmt.Print(prompt + " [y/N] ")
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	line = strings.ToLower(strings.TrimSpace(line))
+	return line == "y" || line == "yes"
+}
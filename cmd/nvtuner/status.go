@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"nvtuner-go/internal/gpu"
+)
+
+func runCaps(args []string) error {
+	fs := flag.NewFlagSet("caps", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print as json")
+	fs.Parse(args)
+
+	drv, err := openDriver()
+	if err != nil {
+		return err
+	}
+	defer drv.Shutdown()
+
+	devs, err := drv.Devices()
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		type entry struct {
+			Index int              `json:"index"`
+			UUID  string           `json:"uuid"`
+			Caps  gpu.Capabilities `json:"capabilities"`
+		}
+		out := make([]entry, len(devs))
+		for i, d := range devs {
+			out[i] = entry{d.GetIndex(), d.GetUUID(), d.GetCapabilities()}
+		}
+		return printJSON(out)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, d := range devs {
+		fmt.Fprintf(w, "GPU %d: %s\n", d.GetIndex(), d.GetName())
+		fmt.Fprintln(w, "  ID\tKIND\tACCESS\tREASON")
+		for _, c := range d.GetCapabilities() {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", c.ID, c.Kind, c.Access, c.Reason)
+		}
+	}
+	return w.Flush()
+}
+
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print as json")
+	fs.Parse(args)
+
+	drv, err := openDriver()
+	if err != nil {
+		return err
+	}
+	defer drv.Shutdown()
+
+	var ms gpu.MState
+	ms.FetchOnce(drv)
+	_, states, err := fetchStates(drv)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(struct {
+			gpu.MState
+			Devices []gpu.DState `json:"devices"`
+		}{ms, states})
+	}
+
+	fmt.Printf("%s %s, driver %s\n", ms.ManagerName, ms.ManagerVersion, ms.DriverVersion)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "IDX\tNAME\tHEALTH\tTEMP\tPOWER\tPL\tGPU_CLK\tMEM_CLK\tUTIL\tENC/DEC\tPCIE")
+	for _, d := range states {
+		pcie := fmt.Sprintf("%s/%s", d.PcieLink, d.PcieMax)
+		if d.PcieBelowMax() {
+			pcie += " (below max)"
+		}
+		codec := fmt.Sprintf("%s/%s", d.Media.UtilEnc, d.Media.UtilDec)
+		if n, ok := d.Media.EncSessions.Get(); ok && n > 0 {
+			codec += fmt.Sprintf(" (%d sessions)", n)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			d.Index, d.Name, d.Health, d.Temp, d.Power, d.Setting("pl"),
+			d.ClockGpu, d.ClockMem, d.UtilGpu, codec, pcie)
+	}
+	return w.Flush()
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -34,6 +34,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create UI model: %v", err)
 	}
+	defer model.Close()
 
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
@@ -5,6 +5,7 @@ go 1.25.5
 require (
 	github.com/NimbleMarkets/ntcharts v0.3.1
 	github.com/ebitengine/purego v0.9.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -2,23 +2,138 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
+	"maps"
 	"os"
 	"sync"
+
+	"nvtuner-go/internal/gpu"
+
+	"gopkg.in/yaml.v3"
 )
 
 const DefaultFileName = "config.json"
 
+// GpuSettings are the configured values of a device's params by ID, in the
+// device's units, so the power limit is in mW. Missing IDs read as 0,
+// which leaves offsets at stock and clock locks open.
 type GpuSettings struct {
-	PowerLimit int `json:"pl"`     // W
-	GpuCO      int `json:"gpu_co"` // MHz
-	MemCO      int `json:"mem_co"` // MHz
-	GpuCL      int `json:"gpu_cl"` // MHz
+	Params map[string]int `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// Get returns the configured value of a parameter. Unknown IDs read as 0.
+func (s GpuSettings) Get(id string) int {
+	return s.Params[id]
+}
+
+// With looks up configured values for ParamDef.Snap, in the params' units.
+func (s GpuSettings) With(d gpu.DInfo) func(id string) gpu.Value {
+	return func(id string) gpu.Value {
+		p, ok := d.Param(id)
+		if !ok {
+			return gpu.Value{}
+		}
+		return p.Unit.Of(s.Get(id))
+	}
+}
+
+// Set stores the value of a parameter, 0 removes it. The map is copied
+// first since settings are passed around by value.
+func (s *GpuSettings) Set(id string, v int) {
+	m := make(map[string]int, len(s.Params)+1)
+	maps.Copy(m, s.Params)
+	if v == 0 {
+		delete(m, id)
+	} else {
+		m[id] = v
+	}
+	if len(m) == 0 {
+		m = nil
+	}
+	s.Params = m
+}
+
+// legacySettings is the layout from before settings were keyed by param
+// ID: the built-in params had fields of their own, the power limit in W.
+// Params held the rest and reads the same in both.
+type legacySettings struct {
+	PowerLimit float64                     `json:"pl" yaml:"pl"`
+	GpuCO      int                         `json:"gpu_co" yaml:"gpu_co"`
+	MemCO      int                         `json:"mem_co" yaml:"mem_co"`
+	PStates    map[int]legacyPStateOffsets `json:"pstates" yaml:"pstates"`
+	GpuCL      int                         `json:"gpu_cl" yaml:"gpu_cl"`
+	GpuCLMin   int                         `json:"gpu_cl_min" yaml:"gpu_cl_min"`
+	MemCL      int                         `json:"mem_cl" yaml:"mem_cl"`
+	MemCLMin   int                         `json:"mem_cl_min" yaml:"mem_cl_min"`
+	AppGpu     int                         `json:"app_gpu" yaml:"app_gpu"`
+	AppMem     int                         `json:"app_mem" yaml:"app_mem"`
+	TempTarget int                         `json:"temp_target" yaml:"temp_target"`
+	Params     map[string]int              `json:"params" yaml:"params"`
+}
+
+type legacyPStateOffsets struct {
+	GpuCO int `json:"gpu_co" yaml:"gpu_co"`
+	MemCO int `json:"mem_co" yaml:"mem_co"`
+}
+
+func (l legacySettings) settings() GpuSettings {
+	s := GpuSettings{Params: l.Params}
+	set := func(id string, v int) {
+		if v != 0 { // unset, don't drop what Params has
+			s.Set(id, v)
+		}
+	}
+	set("pl", gpu.MilliWatts(l.PowerLimit))
+	set("gpu_co", l.GpuCO)
+	set("mem_co", l.MemCO)
+	for ps, o := range l.PStates {
+		set(gpu.PStateParamID("gpu_co", ps), o.GpuCO)
+		set(gpu.PStateParamID("mem_co", ps), o.MemCO)
+	}
+	set("gpu_cl", l.GpuCL)
+	set("gpu_cl_min", l.GpuCLMin)
+	set("mem_cl", l.MemCL)
+	set("mem_cl_min", l.MemCLMin)
+	set("app_gpu", l.AppGpu)
+	set("app_mem", l.AppMem)
+	set("temp_target", l.TempTarget)
+	return s
+}
+
+// UnmarshalJSON also reads the legacy layout, see legacySettings.
+func (s *GpuSettings) UnmarshalJSON(data []byte) error {
+	var l legacySettings
+	if err := json.Unmarshal(data, &l); err != nil {
+		return err
+	}
+	*s = l.settings()
+	return nil
+}
+
+// UnmarshalYAML is UnmarshalJSON for profiles.
+func (s *GpuSettings) UnmarshalYAML(node *yaml.Node) error {
+	var l legacySettings
+	if err := node.Decode(&l); err != nil {
+		return err
+	}
+	*s = l.settings()
+	return nil
 }
 
 type Manager struct {
-	mu       sync.Mutex
-	FilePath string
-	Settings map[string]GpuSettings // Key: GPU UUID
+	mu        sync.Mutex
+	FilePath  string
+	ApplyMode string                 // "all-or-nothing" (default) or "best-effort"
+	Rules     []Rule                 // first match wins; UUID entries in Settings take precedence
+	Settings  map[string]GpuSettings // Key: GPU UUID
+}
+
+// file is the on-disk layout. Older configs are a bare UUID -> settings map,
+// which Load still accepts.
+type file struct {
+	ApplyMode string                 `json:"apply_mode,omitempty"`
+	Rules     []Rule                 `json:"rules,omitempty"`
+	Devices   map[string]GpuSettings `json:"devices"`
 }
 
 func New(path string) *Manager {
@@ -43,14 +158,38 @@ func (m *Manager) Load() error {
 		return err
 	}
 
-	return json.Unmarshal(data, &m.Settings)
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return err
+	}
+	_, hasRules := probe["rules"]
+	_, hasDevices := probe["devices"]
+	_, hasMode := probe["apply_mode"]
+	if !hasRules && !hasDevices && !hasMode {
+		return json.Unmarshal(data, &m.Settings) // legacy layout
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	for i, r := range f.Rules {
+		if err := r.Match.Validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+	if f.Devices == nil {
+		f.Devices = make(map[string]GpuSettings)
+	}
+	m.ApplyMode, m.Rules, m.Settings = f.ApplyMode, f.Rules, f.Devices
+	return nil
 }
 
 func (m *Manager) Save() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	data, err := json.MarshalIndent(m.Settings, "", "    ")
+	data, err := json.MarshalIndent(file{ApplyMode: m.ApplyMode, Rules: m.Rules, Devices: m.Settings}, "", "    ")
 	if err != nil {
 		return err
 	}
@@ -70,3 +209,20 @@ func (m *Manager) Set(uuid string, s GpuSettings) {
 	defer m.mu.Unlock()
 	m.Settings[uuid] = s
 }
+
+// Resolve returns the settings that apply to a device: its UUID entry if
+// present, otherwise the first matching rule.
+func (m *Manager) Resolve(id Identity) (GpuSettings, Source, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.Settings[id.UUID]; ok {
+		return s, Source{Rule: -1}, true
+	}
+	for i, r := range m.Rules {
+		if r.Match.Matches(id) {
+			return r.Settings, Source{Rule: i, Name: r.Name}, true
+		}
+	}
+	return GpuSettings{}, Source{Rule: -1}, false
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	id := Identity{Index: 1, UUID: "GPU-a", Name: "NVIDIA GeForce RTX 4090",
+		PciDevice: 0x268410DE, PciSubsys: 0x88D51043, Board: "900-1G136-2530-000"}
+	one, two := 1, 2
+	tests := []struct {
+		name  string
+		match Match
+		want  bool
+	}{
+		{"empty", Match{}, true},
+		{"model glob", Match{Model: "*RTX 4090*"}, true},
+		{"model case", Match{Model: "*rtx 4090"}, true},
+		{"model other", Match{Model: "*4080*"}, false},
+		{"pci device", Match{PciDevice: "0x2684"}, true},
+		{"pci device and vendor", Match{PciDevice: "0x268410DE"}, true},
+		{"pci vendor only", Match{PciDevice: "0x10DE"}, false},
+		{"pci bad hex", Match{PciDevice: "0xZZ"}, false},
+		{"subsystem", Match{PciSubsys: "88d51043"}, true},
+		{"board glob", Match{Board: "900-1G136-*"}, true},
+		{"index", Match{Index: &one}, true},
+		{"index other", Match{Index: &two}, false},
+		{"all fields", Match{Model: "*4090*", PciDevice: "0x2684", Board: "900-*", Index: &one}, true},
+		{"one field off", Match{Model: "*4090*", PciDevice: "0x2704"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.match.Matches(id); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	m := New("")
+	m.Rules = []Rule{
+		{Name: "4090", Match: Match{Model: "*4090*"}, Settings: GpuSettings{Params: map[string]int{"pl": 400000}}},
+		{Match: Match{PciDevice: "0x2684"}, Settings: GpuSettings{Params: map[string]int{"pl": 350000}}},
+	}
+	m.Settings["GPU-a"] = GpuSettings{Params: map[string]int{"pl": 300000}}
+
+	tests := []struct {
+		name   string
+		id     Identity
+		wantPl int
+		wantOk bool
+		source string
+	}{
+		{"uuid before rules", Identity{UUID: "GPU-a", Name: "RTX 4090", PciDevice: 0x268410DE}, 300000, true, "uuid"},
+		{"first rule wins", Identity{UUID: "GPU-b", Name: "RTX 4090", PciDevice: 0x268410DE}, 400000, true, "rule 0 (4090)"},
+		{"pci rule", Identity{UUID: "GPU-b", Name: "AD102", PciDevice: 0x268410DE}, 350000, true, "rule 1"},
+		{"no match", Identity{UUID: "GPU-b", Name: "RTX 4080", PciDevice: 0x270410DE}, 0, false, "uuid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, src, ok := m.Resolve(tt.id)
+			if ok != tt.wantOk || s.Get("pl") != tt.wantPl || src.String() != tt.source {
+				t.Errorf("Resolve = pl %d from %s, %v; want pl %d from %s, %v",
+					s.Get("pl"), src, ok, tt.wantPl, tt.source, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string // no file if empty
+		mode    string
+		rules   int
+		devices map[string]GpuSettings
+		wantErr bool
+	}{
+		{name: "no file", devices: map[string]GpuSettings{}},
+		{
+			name: "legacy layout",
+			data: `{"GPU-a": {"pl": 212.5, "gpu_co": 100, "gpu_cl": 0, "pstates": {"2": {"mem_co": 500}}, "params": {"fan": 40}}}`,
+			devices: map[string]GpuSettings{"GPU-a": {Params: map[string]int{
+				"pl": 212500, "gpu_co": 100, "mem_co_p2": 500, "fan": 40}}},
+		},
+		{
+			name: "current layout",
+			data: `{"apply_mode": "best-effort", "rules": [{"match": {"model": "*4090*"}, "settings": {"params": {"pl": 300000}}}],
+				"devices": {"GPU-a": {"params": {"gpu_co": 100}}}}`,
+			mode: "best-effort", rules: 1,
+			devices: map[string]GpuSettings{"GPU-a": {Params: map[string]int{"gpu_co": 100}}},
+		},
+		{
+			name:    "legacy settings in the current layout",
+			data:    `{"devices": {"GPU-a": {"pl": 250, "params": {"gpu_cl": 1500}}}}`,
+			devices: map[string]GpuSettings{"GPU-a": {Params: map[string]int{"pl": 250000, "gpu_cl": 1500}}},
+		},
+		{
+			name:    "rules only",
+			data:    `{"rules": [{"match": {}, "settings": {}}]}`,
+			rules:   1,
+			devices: map[string]GpuSettings{},
+		},
+		{name: "bad glob", data: `{"rules": [{"match": {"model": "["}}]}`, wantErr: true},
+		{name: "bad pci id", data: `{"rules": [{"match": {"pci_device": "0xZZ"}}]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), DefaultFileName)
+			if tt.data != "" {
+				if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			m := New(path)
+			err := m.Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if m.ApplyMode != tt.mode || len(m.Rules) != tt.rules || !reflect.DeepEqual(m.Settings, tt.devices) {
+				t.Errorf("loaded mode %q, %d rules, devices %v; want %q, %d, %v",
+					m.ApplyMode, len(m.Rules), m.Settings, tt.mode, tt.rules, tt.devices)
+			}
+
+			// what was loaded survives a save in the current layout
+			if err := m.Save(); err != nil {
+				t.Fatal(err)
+			}
+			again := New(path)
+			if err := again.Load(); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(again.Settings, m.Settings) || !reflect.DeepEqual(again.Rules, m.Rules) {
+				t.Errorf("reloaded %v, %v; saved %v, %v", again.Settings, again.Rules, m.Settings, m.Rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"nvtuner-go/internal/gpu"
+)
+
+// Rule applies Settings to every device its Match selects.
+type Rule struct {
+	Name     string      `json:"name,omitempty"`
+	Match    Match       `json:"match"`
+	Settings GpuSettings `json:"settings"`
+}
+
+// Match selects devices. Empty fields are ignored, so an empty Match selects
+// every device. All non-empty fields must match.
+type Match struct {
+	Model     string `json:"model,omitempty"`         // glob, e.g. "*RTX 4090*"
+	PciDevice string `json:"pci_device,omitempty"`    // hex, "0x2684" or "0x268410DE"
+	PciSubsys string `json:"pci_subsystem,omitempty"` // hex, e.g. "0x88D51043"
+	Board     string `json:"board,omitempty"`         // glob on board part number
+	Index     *int   `json:"index,omitempty"`
+}
+
+// Identity is what rules are matched against.
+type Identity struct {
+	Index     int
+	UUID      string
+	Name      string
+	PciDevice uint32 // device id << 16 | vendor id
+	PciSubsys uint32
+	Board     string
+}
+
+// Source tells where resolved settings came from.
+type Source struct {
+	Rule int // index into Manager.Rules, -1 for a UUID entry
+	Name string
+}
+
+func IdentityOf(d gpu.DState) Identity {
+	return Identity{
+		Index:     d.Index,
+		UUID:      d.UUID,
+		Name:      d.Name,
+		PciDevice: d.Pci.DeviceID,
+		PciSubsys: d.Pci.SubsystemID,
+		Board:     d.Board,
+	}
+}
+
+func (s Source) String() string {
+	switch {
+	case s.Rule < 0:
+		return "uuid"
+	case s.Name != "":
+		return fmt.Sprintf("rule %d (%s)", s.Rule, s.Name)
+	default:
+		return fmt.Sprintf("rule %d", s.Rule)
+	}
+}
+
+func (m Match) Matches(id Identity) bool {
+	if m.Model != "" && !globMatch(m.Model, id.Name) {
+		return false
+	}
+	if m.PciDevice != "" && !pciMatch(m.PciDevice, id.PciDevice) {
+		return false
+	}
+	if m.PciSubsys != "" && !pciMatch(m.PciSubsys, id.PciSubsys) {
+		return false
+	}
+	if m.Board != "" && !globMatch(m.Board, id.Board) {
+		return false
+	}
+	if m.Index != nil && *m.Index != id.Index {
+		return false
+	}
+	return true
+}
+
+func (m Match) Validate() error {
+	for _, g := range []string{m.Model, m.Board} {
+		if _, err := path.Match(g, ""); err != nil {
+			return fmt.Errorf("bad glob %q: %w", g, err)
+		}
+	}
+	for _, h := range []string{m.PciDevice, m.PciSubsys} {
+		if h == "" {
+			continue
+		}
+		if _, err := parseHex(h); err != nil {
+			return fmt.Errorf("bad pci id %q: %w", h, err)
+		}
+	}
+	return nil
+}
+
+func globMatch(pattern, s string) bool {
+	ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(s))
+	return ok
+}
+
+// pciMatch compares a full 32-bit id, or only the upper 16 bits (the device
+// id without the vendor) when the pattern fits in 16 bits.
+func pciMatch(pattern string, id uint32) bool {
+	v, err := parseHex(pattern)
+	if err != nil {
+		return false
+	}
+	if v <= 0xFFFF {
+		return v == id>>16
+	}
+	return v == id
+}
+
+func parseHex(s string) (uint32, error) {
+	s = strings.TrimPrefix(strings.ToLower(s), "0x")
+	v, err := strconv.ParseUint(s, 16, 32)
+	return uint32(v), err
+}
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"nvtuner-go/internal/gpu"
+)
+
+type Severity int
+
+const (
+	SeverityWarning Severity = iota
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Issue is a single finding of Check. Entry is a UUID or "rule N".
+type Issue struct {
+	Severity Severity
+	Entry    string
+	Field    string
+	Msg      string
+	Fixed    bool
+}
+
+func (i Issue) String() string {
+	s := fmt.Sprintf("%s: %s", i.Severity, i.Entry)
+	if i.Field != "" {
+		s += " " + i.Field
+	}
+	s += ": " + i.Msg
+	if i.Fixed {
+		s += " (fixed)"
+	}
+	return s
+}
+
+// format renders a value of a param for messages, like "250 W".
+func format(p gpu.ParamDef, v int) string {
+	return strings.TrimSpace(p.Format(v) + " " + p.DisplayUnit())
+}
+
+// Check validates every UUID entry and rule against the live devices. With
+// fix set, out-of-range values are clamped and entries of absent GPUs are
+// pruned; the caller still has to Save.
+func (m *Manager) Check(states []gpu.DState, fix bool) []Issue {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var issues []Issue
+	byUUID := make(map[string]gpu.DState, len(states))
+	for _, d := range states {
+		byUUID[d.UUID] = d
+	}
+
+	for uuid, s := range m.Settings {
+		d, ok := byUUID[uuid]
+		if !ok {
+			issues = append(issues, Issue{SeverityWarning, uuid, "", "no such GPU on this machine", fix})
+			if fix {
+				delete(m.Settings, uuid)
+			}
+			continue
+		}
+		issues = append(issues, checkSettings(&s, d, uuid, fix)...)
+		m.Settings[uuid] = s
+	}
+
+	for i := range m.Rules {
+		r := &m.Rules[i]
+		entry := Source{Rule: i, Name: r.Name}.String()
+		matched := false
+		for _, d := range states {
+			if !r.Match.Matches(IdentityOf(d)) {
+				continue
+			}
+			matched = true
+			if _, ok := m.Settings[d.UUID]; ok {
+				continue // shadowed by the uuid entry
+			}
+			issues = append(issues, checkSettings(&r.Settings, d, entry, fix)...)
+		}
+		if !matched {
+			issues = append(issues, Issue{SeverityWarning, entry, "", "matches no GPU on this machine", false})
+		}
+	}
+
+	return issues
+}
+
+func checkSettings(s *GpuSettings, d gpu.DState, entry string, fix bool) []Issue {
+	var issues []Issue
+
+	for _, p := range d.Params {
+		v := s.Get(p.ID)
+		lo, hi, ok := p.Range()
+		if !ok {
+			continue // device can't tell, nothing to check against
+		}
+
+		switch p.Kind {
+		case gpu.KindLockMax, gpu.KindLockMin:
+			issues = append(issues, checkLock(p, s, d, entry, fix)...)
+			continue
+		case gpu.KindAppClock:
+			continue // see checkAppClocks
+		}
+		if p.Unset(v) || v >= lo && v <= hi {
+			continue
+		}
+
+		msg := fmt.Sprintf("%s out of range [%s, %s] on GPU %d (%s)", format(p, v), format(p, lo), format(p, hi), d.Index, d.Name)
+		issues = append(issues, Issue{SeverityError, entry, p.ID, msg, fix})
+		if fix {
+			s.Set(p.ID, min(max(v, lo), hi))
+		}
+	}
+
+	issues = append(issues, checkLockRange(s, d, entry, "gpu_cl", fix)...)
+	issues = append(issues, checkLockRange(s, d, entry, "mem_cl", fix)...)
+	issues = append(issues, checkAppClocks(s, d, entry, fix)...)
+
+	for id := range s.Params {
+		if _, ok := d.Param(id); ok || len(d.Params) == 0 {
+			continue
+		}
+		issues = append(issues, Issue{SeverityWarning, entry, id,
+			fmt.Sprintf("not a parameter of GPU %d (%s)", d.Index, d.Name), fix})
+		if fix {
+			s.Set(id, 0)
+		}
+	}
+
+	if p, ok := d.Param("pl"); ok {
+		if pl := s.Get(p.ID); pl > 0 && p.Default.Valid && pl < p.Default.V/2 {
+			issues = append(issues, Issue{SeverityWarning, entry, p.ID,
+				fmt.Sprintf("%s is less than half of the default %s", format(p, pl), format(p, p.Default.V)), false})
+		}
+	}
+
+	return issues
+}
+
+// checkLock checks one bound of a clock lock: open bounds are fine, a max
+// below the lowest clock is an error and clocks not in the table get
+// snapped.
+func checkLock(p gpu.ParamDef, s *GpuSettings, d gpu.DState, entry string, fix bool) []Issue {
+	v := s.Get(p.ID)
+	lo, _, _ := p.Range() // checked by the caller
+	if p.Unset(v) {
+		return nil
+	}
+
+	if v < lo {
+		msg := fmt.Sprintf("%d MHz is below the minimum supported clock %d MHz on GPU %d (%s)", v, lo, d.Index, d.Name)
+		if fix {
+			s.Set(p.ID, lo)
+		}
+		return []Issue{{SeverityError, entry, p.ID, msg, fix}}
+	}
+
+	snapped := p.SnapLock(v)
+	if snapped == v {
+		return nil
+	}
+	msg := fmt.Sprintf("%d MHz is not a supported clock on GPU %d (%s), %d MHz will be used", v, d.Index, d.Name, snapped)
+	if fix {
+		s.Set(p.ID, snapped)
+	}
+	return []Issue{{SeverityWarning, entry, p.ID, msg, fix}}
+}
+
+// checkLockRange makes sure the lower bound of a lock is not above the upper.
+func checkLockRange(s *GpuSettings, d gpu.DState, entry, name string, fix bool) []Issue {
+	hiP, ok1 := d.Param(name)
+	loP, ok2 := d.Param(name + "_min")
+	if !ok1 || !ok2 {
+		return nil
+	}
+	minV, maxV := s.Get(loP.ID), s.Get(hiP.ID)
+	if _, _, ok := hiP.Range(); !ok || loP.Unset(minV) || hiP.Unset(maxV) || minV <= maxV {
+		return nil
+	}
+	msg := fmt.Sprintf("%d MHz is above %s %d MHz", minV, name, maxV)
+	if fix {
+		s.Set(loP.ID, maxV)
+	}
+	return []Issue{{SeverityError, entry, loP.ID, msg, fix}}
+}
+
+// checkAppClocks warns about application clocks that aren't a supported
+// pair and would be snapped when applied.
+func checkAppClocks(s *GpuSettings, d gpu.DState, entry string, fix bool) []Issue {
+	gp, ok1 := d.Param("app_gpu")
+	mp, ok2 := d.Param("app_mem")
+	if !ok1 || !ok2 || gp.Snap == nil || mp.Snap == nil {
+		return nil
+	}
+	g, m := s.Get(gp.ID), s.Get(mp.ID)
+	if g <= 0 && m <= 0 {
+		return nil
+	}
+	mclk := mp.Snap(d, m, s.With(d.DInfo))
+	gclk := gp.Snap(d, g, func(string) gpu.Value { return mclk })
+	if !gclk.Valid || !mclk.Valid || (g <= 0 || gclk.V == g) && (m <= 0 || mclk.V == m) {
+		return nil
+	}
+	msg := fmt.Sprintf("%d/%d MHz is not a supported gpu/mem pair on GPU %d (%s), %d/%d MHz will be used",
+		g, m, d.Index, d.Name, gclk.V, mclk.V)
+	if fix {
+		s.Set(gp.ID, gclk.V)
+		s.Set(mp.ID, mclk.V)
+	}
+	return []Issue{{SeverityWarning, entry, gp.ID, msg, fix}}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"maps"
+	"reflect"
+	"testing"
+
+	"nvtuner-go/internal/gpu"
+	"nvtuner-go/internal/gpu/gputest"
+)
+
+type issueKey struct {
+	Severity Severity
+	Field    string
+}
+
+func keysOf(issues []Issue) []issueKey {
+	var res []issueKey
+	for _, i := range issues {
+		res = append(res, issueKey{i.Severity, i.Field})
+	}
+	return res
+}
+
+func TestCheck(t *testing.T) {
+	states := []gpu.DState{gputest.State(gputest.New())}
+	tests := []struct {
+		name   string
+		params map[string]int // pl is added
+		issues []issueKey
+		fixed  map[string]int // what fix leaves, pl included
+	}{
+		{"in range", map[string]int{"gpu_co": 105, "gpu_cl": 1500, gpu.PStateParamID("gpu_co", 2): -90, "app_gpu": 1005}, nil, nil},
+		{"above max", map[string]int{"pl": 300000},
+			[]issueKey{{SeverityError, "pl"}}, map[string]int{"pl": 250000}},
+		{"below min", map[string]int{"gpu_co": -300},
+			[]issueKey{{SeverityError, "gpu_co"}}, map[string]int{"gpu_co": -200}},
+		{"lock off the table", map[string]int{"gpu_cl": 1600},
+			[]issueKey{{SeverityWarning, "gpu_cl"}}, map[string]int{"gpu_cl": 1500}},
+		{"lock below the lowest clock", map[string]int{"gpu_cl": 100},
+			[]issueKey{{SeverityError, "gpu_cl"}}, map[string]int{"gpu_cl": 210}},
+		{"lock min above max", map[string]int{"gpu_cl": 1005, "gpu_cl_min": 1500},
+			[]issueKey{{SeverityError, "gpu_cl_min"}}, map[string]int{"gpu_cl": 1005, "gpu_cl_min": 1005}},
+		{"app clocks off the table", map[string]int{"app_gpu": 1600, "app_mem": 5001},
+			[]issueKey{{SeverityWarning, "app_gpu"}}, map[string]int{"app_gpu": 1500, "app_mem": 5001}},
+		{"app clock of another memory clock", map[string]int{"app_gpu": 1005, "app_mem": 405},
+			[]issueKey{{SeverityWarning, "app_gpu"}}, map[string]int{"app_gpu": 405, "app_mem": 405}},
+		{"unknown param", map[string]int{"fan": 40},
+			[]issueKey{{SeverityWarning, "fan"}}, map[string]int{}},
+		{"unsupported P-state", map[string]int{gpu.PStateParamID("gpu_co", 3): 50},
+			[]issueKey{{SeverityWarning, "gpu_co_p3"}}, map[string]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := map[string]int{"pl": 200000}
+			maps.Copy(params, tt.params)
+
+			for _, fix := range []bool{false, true} {
+				m := New("")
+				m.Settings["GPU-fake"] = GpuSettings{Params: maps.Clone(params)}
+				issues := m.Check(states, fix)
+				if got := keysOf(issues); !reflect.DeepEqual(got, tt.issues) {
+					t.Fatalf("fix %v: issues %v, want %v", fix, issues, tt.issues)
+				}
+				for _, i := range issues {
+					if i.Fixed != fix {
+						t.Errorf("fix %v: %v", fix, i)
+					}
+				}
+
+				want := params
+				if fix && tt.fixed != nil {
+					want = map[string]int{"pl": 200000}
+					maps.Copy(want, tt.fixed)
+				}
+				if got := m.Settings["GPU-fake"].Params; !reflect.DeepEqual(got, want) {
+					t.Errorf("fix %v: settings %v, want %v", fix, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckEntries(t *testing.T) {
+	states := []gpu.DState{gputest.State(gputest.New())}
+	bad := GpuSettings{Params: map[string]int{"pl": 300000}}
+
+	m := New("")
+	m.Settings["GPU-gone"] = bad
+	m.Rules = []Rule{
+		{Match: Match{Model: "Fake*"}, Settings: bad},  // matches, checked
+		{Match: Match{Model: "Other*"}, Settings: bad}, // matches nothing
+	}
+	want := []issueKey{{SeverityWarning, ""}, {SeverityError, "pl"}, {SeverityWarning, ""}}
+	if got := keysOf(m.Check(states, true)); !reflect.DeepEqual(got, want) {
+		t.Fatalf("issues %v, want %v", got, want)
+	}
+	if _, ok := m.Settings["GPU-gone"]; ok {
+		t.Error("entry of an absent GPU kept")
+	}
+	if pl := m.Rules[0].Settings.Get("pl"); pl != 250000 {
+		t.Errorf("matching rule's pl = %d, want it clamped", pl)
+	}
+
+	// a UUID entry shadows the rules matching the same GPU
+	m.Settings["GPU-fake"] = GpuSettings{Params: map[string]int{"pl": 200000}}
+	m.Rules[0].Settings = bad
+	want = []issueKey{{SeverityWarning, ""}}
+	if got := keysOf(m.Check(states, false)); !reflect.DeepEqual(got, want) {
+		t.Errorf("with a uuid entry: issues %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,115 @@
+package nvidia
+
+import (
+	"fmt"
+
+	"nvtuner-go/internal/gpu"
+)
+
+func (g *NvidiaGpu) GetAppClockGpu() (int, error) {
+	return g.getAppClock(g.symbols.DeviceGetApplicationsClock, "nvmlDeviceGetApplicationsClock", CLOCK_GRAPHICS)
+}
+
+func (g *NvidiaGpu) GetAppClockMem() (int, error) {
+	return g.getAppClock(g.symbols.DeviceGetApplicationsClock, "nvmlDeviceGetApplicationsClock", CLOCK_MEM)
+}
+
+func (g *NvidiaGpu) GetAppClockDefault() (int, int, error) {
+	gclk, err := g.getAppClock(g.symbols.DeviceGetDefaultApplicationsClock, "nvmlDeviceGetDefaultApplicationsClock", CLOCK_GRAPHICS)
+	if err != nil {
+		return gpu.NO_VALUE, gpu.NO_VALUE, err
+	}
+	mclk, err := g.getAppClock(g.symbols.DeviceGetDefaultApplicationsClock, "nvmlDeviceGetDefaultApplicationsClock", CLOCK_MEM)
+	if err != nil {
+		return gpu.NO_VALUE, gpu.NO_VALUE, err
+	}
+	return gclk, mclk, nil
+}
+
+func (g *NvidiaGpu) getAppClock(fn func(Device, ClockType, *uint32) Return, name string, typ ClockType) (int, error) {
+	if fn == nil {
+		return gpu.NO_VALUE, errMissing(name)
+	}
+	var clk uint32
+	if ret := fn(g.handle, typ, &clk); ret != SUCCESS {
+		return gpu.NO_VALUE, g.symbols.Error(ret)
+	}
+	return int(clk), nil
+}
+
+// SetAppClockGpu keeps the memory application clock and snaps mhz to the
+// graphics clocks supported with it. The memory clock is snapped onto the
+// table first, the driver may report one slightly off.
+func (g *NvidiaGpu) SetAppClockGpu(mhz int) error {
+	t, err := g.GetSupportedClocks()
+	if err != nil {
+		return err
+	}
+	mclk, err := g.GetAppClockMem()
+	if err != nil {
+		return fmt.Errorf("failed to get mem app clock: %w", err)
+	}
+	if mhz <= 0 {
+		if mhz, _, err = g.GetAppClockDefault(); err != nil {
+			return fmt.Errorf("failed to get default app clocks: %w", err)
+		}
+	}
+	mclk = gpu.SnapDown(t.Mem, mclk)
+	return g.setAppClocks(gpu.SnapDown(t.Gpu[mclk], mhz), mclk)
+}
+
+// SetAppClockMem snaps mhz to the memory clock table and moves the graphics
+// application clock down if the new memory clock doesn't support it.
+func (g *NvidiaGpu) SetAppClockMem(mhz int) error {
+	t, err := g.GetSupportedClocks()
+	if err != nil {
+		return err
+	}
+	gclk, err := g.GetAppClockGpu()
+	if err != nil {
+		return fmt.Errorf("failed to get gpu app clock: %w", err)
+	}
+	if mhz <= 0 {
+		if _, mhz, err = g.GetAppClockDefault(); err != nil {
+			return fmt.Errorf("failed to get default app clocks: %w", err)
+		}
+	}
+	mclk := gpu.SnapDown(t.Mem, mhz)
+	return g.setAppClocks(gpu.SnapDown(t.Gpu[mclk], gclk), mclk)
+}
+
+func (g *NvidiaGpu) setAppClocks(gclk, mclk int) error {
+	if g.symbols.DeviceSetApplicationsClocks == nil {
+		return errMissing("nvmlDeviceSetApplicationsClocks")
+	}
+	if ret := g.symbols.DeviceSetApplicationsClocks(g.handle, uint32(mclk), uint32(gclk)); ret != SUCCESS {
+		return g.symbols.Error(ret)
+	}
+	return nil
+}
+
+func (g *NvidiaGpu) ResetAppClocks() error {
+	if g.symbols.DeviceResetApplicationsClocks == nil {
+		return errMissing("nvmlDeviceResetApplicationsClocks")
+	}
+	if ret := g.symbols.DeviceResetApplicationsClocks(g.handle); ret != SUCCESS {
+		return g.symbols.Error(ret)
+	}
+	return nil
+}
+
+// snapAppClocks moves application clocks onto a pair of t, filling in the
+// defaults for unset ones.
+func snapAppClocks(gclk, mclk int, t gpu.ClockTable, defGpu, defMem gpu.Value) (int, int) {
+	if len(t.Mem) == 0 {
+		return gclk, mclk
+	}
+	if mclk <= 0 {
+		mclk = defMem.Or(mclk)
+	}
+	if gclk <= 0 {
+		gclk = defGpu.Or(gclk)
+	}
+	mclk = gpu.SnapDown(t.Mem, mclk)
+	return gpu.SnapDown(t.Gpu[mclk], gclk), mclk
+}
@@ -0,0 +1,177 @@
+package nvidia
+
+import (
+	"fmt"
+	"os"
+
+	"nvtuner-go/internal/gpu"
+)
+
+func (g *NvidiaGpu) GetCapabilities() gpu.Capabilities {
+	g.capsOnce.Do(func() { g.caps = g.probeCapabilities() })
+	return g.caps
+}
+
+// probeCapabilities calls every getter once and looks at which setters the
+// library exports. Setters are never called, so writability is a best guess:
+// the driver may still refuse a value.
+func (g *NvidiaGpu) probeCapabilities() gpu.Capabilities {
+	s := g.symbols
+	var err error
+	caps := gpu.Capabilities{}
+	metric := func(id string, err error) {
+		caps = append(caps, gpu.ProbeRead(id, gpu.CapMetric, err))
+	}
+
+	_, _, err = g.GetUtil()
+	metric("util", err)
+	_, _, err = g.GetClocks()
+	metric("clocks", err)
+	_, _, _, err = g.GetMemory()
+	metric("memory", err)
+	_, err = g.GetPower()
+	metric("power", err)
+	_, err = g.GetTemperature()
+	metric("temp", err)
+	_, err = g.getTempThreshold(TEMPERATURE_THRESHOLD_SLOWDOWN)
+	metric("temp_thresholds", err)
+	_, _, err = g.GetFanSpeed()
+	metric("fan", err)
+	_, err = g.GetPState()
+	metric("pstate", err)
+	_, err = g.GetPcieLink()
+	metric("pcie_link", err)
+	// the byte counters spare the 40ms nvmlDeviceGetPcieThroughput takes
+	if err = g.probeField(FI_DEV_PCIE_COUNT_TX_BYTES); err != nil {
+		_, _, err = g.GetPcieThroughput()
+	}
+	metric("pcie_throughput", err)
+	_, _, err = g.getEccMode()
+	metric("ecc", err)
+	_, err = g.getEngineUtil(s.DeviceGetEncoderUtilization, "nvmlDeviceGetEncoderUtilization")
+	metric("encoder", err)
+	_, err = g.getEngineUtil(s.DeviceGetDecoderUtilization, "nvmlDeviceGetDecoderUtilization")
+	metric("decoder", err)
+
+	// writes need root on linux; geteuid is -1 on windows
+	var errPriv error
+	if os.Geteuid() > 0 {
+		errPriv = fmt.Errorf("%w: needs root", gpu.ErrNoPermission)
+	}
+	param := func(id string, readErr, writeErr error) {
+		if writeErr == nil {
+			writeErr = errPriv
+		}
+		caps = append(caps, gpu.ProbeParam(id, readErr, writeErr))
+	}
+
+	// pl
+	var readErr, writeErr error
+	_, readErr = g.GetPl()
+	switch {
+	case s.DeviceSetPowerManagementLimit == nil:
+		writeErr = errMissing("nvmlDeviceSetPowerManagementLimit")
+	case !g.CanSetPl():
+		writeErr = fmt.Errorf("%w: controlled by vbios/hardware", gpu.ErrNotSupported)
+	default:
+		_, _, writeErr = g.GetPlLim()
+	}
+	param("pl", readErr, writeErr)
+
+	// clock offsets, the legacy calls only exist on older drivers
+	offsets := s.DeviceGetClockOffsets != nil && s.DeviceSetClockOffsets != nil
+	readErr, writeErr = nil, nil
+	switch {
+	case !offsets && (s.DeviceGetGpcClkVfOffset == nil || s.DeviceSetGpcClkVfOffset == nil):
+		readErr = errMissing("nvmlDeviceGetClockOffsets")
+		writeErr = errMissing("nvmlDeviceSetClockOffsets")
+	default:
+		_, readErr = g.GetCoGpu()
+		_, _, writeErr = g.GetCoLimGpu()
+	}
+	param("gpu_co", readErr, writeErr)
+
+	readErr, writeErr = nil, nil
+	switch {
+	case !offsets && (s.DeviceGetMemClkVfOffset == nil || s.DeviceSetMemClkVfOffset == nil):
+		readErr = errMissing("nvmlDeviceGetClockOffsets")
+		writeErr = errMissing("nvmlDeviceSetClockOffsets")
+	default:
+		_, readErr = g.GetCoMem()
+		_, _, writeErr = g.GetCoLimMem()
+	}
+	param("mem_co", readErr, writeErr)
+
+	// offsets of the other P-states, only the versioned calls reach them
+	pstates, _ := g.GetPStates()
+	for _, ps := range pstates {
+		if ps == 0 {
+			continue
+		}
+		readErr, writeErr = nil, nil
+		if !offsets {
+			readErr, writeErr = legacyPState(ps), legacyPState(ps)
+		} else {
+			_, readErr = g.GetCoGpuAt(ps)
+			_, _, writeErr = g.GetCoLimGpuAt(ps)
+		}
+		param(gpu.PStateParamID("gpu_co", ps), readErr, writeErr)
+
+		if offsets {
+			_, readErr = g.GetCoMemAt(ps)
+			_, _, writeErr = g.GetCoLimMemAt(ps)
+		}
+		param(gpu.PStateParamID("mem_co", ps), readErr, writeErr)
+	}
+
+	// clock locks, read back from what was set through this handle
+	switch {
+	case s.DeviceSetGpuLockedClocks == nil:
+		writeErr = errMissing("nvmlDeviceSetGpuLockedClocks")
+	case s.DeviceResetGpuLockedClocks == nil:
+		writeErr = errMissing("nvmlDeviceResetGpuLockedClocks")
+	default:
+		_, _, writeErr = g.GetClLimGpu()
+	}
+	param("gpu_cl", writeErr, writeErr)
+	param("gpu_cl_min", writeErr, writeErr)
+
+	switch {
+	case s.DeviceSetMemoryLockedClocks == nil:
+		writeErr = errMissing("nvmlDeviceSetMemoryLockedClocks")
+	case s.DeviceResetMemoryLockedClocks == nil:
+		writeErr = errMissing("nvmlDeviceResetMemoryLockedClocks")
+	default:
+		_, _, writeErr = g.GetClLimMem()
+	}
+	param("mem_cl", writeErr, writeErr)
+	param("mem_cl_min", writeErr, writeErr)
+
+	// application clocks; most consumer cards read them but refuse to set
+	_, readErr = g.GetAppClockGpu()
+	switch {
+	case s.DeviceSetApplicationsClocks == nil:
+		writeErr = errMissing("nvmlDeviceSetApplicationsClocks")
+	case s.DeviceResetApplicationsClocks == nil:
+		writeErr = errMissing("nvmlDeviceResetApplicationsClocks")
+	default:
+		_, _, writeErr = g.GetAppClockDefault()
+		if writeErr == nil {
+			_, writeErr = g.GetSupportedClocks()
+		}
+	}
+	param("app_gpu", readErr, writeErr)
+	_, readErr = g.GetAppClockMem()
+	param("app_mem", readErr, writeErr)
+
+	// acoustic temperature target
+	_, readErr = g.GetTempTarget()
+	if s.DeviceSetTemperatureThreshold == nil {
+		writeErr = errMissing("nvmlDeviceSetTemperatureThreshold")
+	} else {
+		_, _, writeErr = g.GetTempTargetLim()
+	}
+	param("temp_target", readErr, writeErr)
+
+	return caps
+}
@@ -0,0 +1,293 @@
+package nvidia
+
+import (
+	"fmt"
+	"slices"
+
+	"nvtuner-go/internal/gpu"
+)
+
+// clockLock is a locked clock range; NO_VALUE bounds are open. NVML has no
+// getter for locks, so it is only known once set through this handle.
+type clockLock struct {
+	min, max int
+	wantMin  int // min asked for while a lower max clamped it, NO_VALUE if none
+	known    bool
+}
+
+var (
+	noLock      = clockLock{gpu.NO_VALUE, gpu.NO_VALUE, gpu.NO_VALUE, true}
+	unknownLock = clockLock{gpu.NO_VALUE, gpu.NO_VALUE, gpu.NO_VALUE, false}
+)
+
+// withMax is l with a new max; a min clamped by the old one is asked for
+// again.
+func (l clockLock) withMax(mhz int) clockLock {
+	l.max = mhz
+	if l.wantMin != gpu.NO_VALUE {
+		l.min = l.wantMin
+	}
+	return l
+}
+
+// withMin is l with a new min.
+func (l clockLock) withMin(mhz int) clockLock {
+	l.min, l.wantMin = mhz, gpu.NO_VALUE
+	return l
+}
+
+func (l clockLock) open() bool { return l.min == gpu.NO_VALUE && l.max == gpu.NO_VALUE }
+
+// lockSlack is how far above a locked max a clock may be observed before
+// the lock is taken as gone. Clocks step in 15 MHz bins.
+const lockSlack = 30
+
+// GetSupportedClocks reads the clock tables until that succeeds once; they
+// don't change while the driver is loaded.
+func (g *NvidiaGpu) GetSupportedClocks() (gpu.ClockTable, error) {
+	g.clocksMu.Lock()
+	defer g.clocksMu.Unlock()
+	if g.clocks.Mem != nil {
+		return g.clocks, nil
+	}
+	t, err := g.readSupportedClocks()
+	if err != nil {
+		return t, err
+	}
+	g.clocks, g.gpuClocks = t, t.GpuClocks()
+	return t, nil
+}
+
+func (g *NvidiaGpu) readSupportedClocks() (gpu.ClockTable, error) {
+	t := gpu.ClockTable{Gpu: map[int][]int{}}
+
+	mem, err := g.getSupportedMemClocks()
+	if err != nil {
+		return t, fmt.Errorf("failed to get supported mem clocks: %w", err)
+	}
+	if len(mem) == 0 {
+		return t, fmt.Errorf("%w: no supported mem clocks", gpu.ErrNotSupported)
+	}
+	slices.Sort(mem)
+	t.Mem = mem
+
+	for _, m := range mem {
+		cs, err := g.getSupportedGpuClocks(m)
+		if err != nil {
+			return t, fmt.Errorf("failed to get gpu clocks for mem %d: %w", m, err)
+		}
+		slices.Sort(cs)
+		t.Gpu[m] = cs
+	}
+	return t, nil
+}
+
+func (g *NvidiaGpu) GetClLimMem() (int, int, error) {
+	t, err := g.GetSupportedClocks()
+	if err != nil {
+		return gpu.NO_VALUE, gpu.NO_VALUE, err
+	}
+	return t.Mem[0], t.Mem[len(t.Mem)-1], nil
+}
+
+func (g *NvidiaGpu) GetClGpu() (int, error) {
+	_, max, err := g.getLocks(CLOCK_GRAPHICS)
+	return max, err
+}
+
+func (g *NvidiaGpu) GetClGpuMin() (int, error) {
+	min, _, err := g.getLocks(CLOCK_GRAPHICS)
+	return min, err
+}
+
+func (g *NvidiaGpu) GetClMem() (int, error) {
+	_, max, err := g.getLocks(CLOCK_MEM)
+	return max, err
+}
+
+func (g *NvidiaGpu) GetClMemMin() (int, error) {
+	min, _, err := g.getLocks(CLOCK_MEM)
+	return min, err
+}
+
+// getLocks reads one clock to verify the tracked lock of its domain and
+// returns the lock's bounds.
+func (g *NvidiaGpu) getLocks(typ ClockType) (int, int, error) {
+	var clk uint32
+	observed := gpu.NO_VALUE
+	if g.symbols.DeviceGetClockInfo(g.handle, typ, &clk) == SUCCESS {
+		observed = int(clk)
+	}
+	var gl, ml clockLock
+	if typ == CLOCK_MEM {
+		_, ml = g.verifyLocks(gpu.NO_VALUE, observed)
+		return g.lockBounds(ml, CLOCK_MEM)
+	}
+	gl, _ = g.verifyLocks(observed, gpu.NO_VALUE)
+	return g.lockBounds(gl, CLOCK_GRAPHICS)
+}
+
+// verifyLocks drops tracked locks that observed clocks show are no longer in
+// effect, e.g. because another tool reset them. NO_VALUE clocks are ignored.
+func (g *NvidiaGpu) verifyLocks(gclk, mclk int) (clockLock, clockLock) {
+	g.lockMu.Lock()
+	defer g.lockMu.Unlock()
+
+	verify := func(l clockLock, observed int) clockLock {
+		if l.known && l.max != gpu.NO_VALUE && observed != gpu.NO_VALUE && observed > l.max+lockSlack {
+			return unknownLock
+		}
+		return l
+	}
+	g.gpuLock = verify(g.gpuLock, gclk)
+	g.memLock = verify(g.memLock, mclk)
+	return g.gpuLock, g.memLock
+}
+
+// lockBounds turns a lock into min and max clocks, the ends of the table
+// standing in for open sides.
+func (g *NvidiaGpu) lockBounds(l clockLock, typ ClockType) (int, int, error) {
+	if !l.known {
+		return gpu.NO_VALUE, gpu.NO_VALUE, gpu.ErrLockUnknown
+	}
+	t, err := g.GetSupportedClocks()
+	if err != nil {
+		return gpu.NO_VALUE, gpu.NO_VALUE, err
+	}
+	table := t.Mem
+	if typ == CLOCK_GRAPHICS {
+		table = g.gpuClocks
+	}
+	if len(table) == 0 {
+		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf("%w: no supported clocks", gpu.ErrNotSupported)
+	}
+
+	min, max := table[0], table[len(table)-1]
+	if l.min != gpu.NO_VALUE {
+		min = l.min
+	}
+	if l.max != gpu.NO_VALUE {
+		max = l.max
+	}
+	return min, max, nil
+}
+
+func (g *NvidiaGpu) SetClGpu(mhz int) error {
+	g.lockMu.Lock()
+	defer g.lockMu.Unlock()
+	return g.lockGpu(g.gpuLock.withMax(mhz))
+}
+
+func (g *NvidiaGpu) SetClGpuMin(mhz int) error {
+	g.lockMu.Lock()
+	defer g.lockMu.Unlock()
+	return g.lockGpu(g.gpuLock.withMin(mhz))
+}
+
+func (g *NvidiaGpu) ResetClGpu() error {
+	g.lockMu.Lock()
+	defer g.lockMu.Unlock()
+	return g.lockGpu(noLock)
+}
+
+func (g *NvidiaGpu) SetClMem(mhz int) error {
+	g.lockMu.Lock()
+	defer g.lockMu.Unlock()
+	return g.lockMem(g.memLock.withMax(mhz))
+}
+
+func (g *NvidiaGpu) SetClMemMin(mhz int) error {
+	g.lockMu.Lock()
+	defer g.lockMu.Unlock()
+	return g.lockMem(g.memLock.withMin(mhz))
+}
+
+func (g *NvidiaGpu) ResetClMem() error {
+	g.lockMu.Lock()
+	defer g.lockMu.Unlock()
+	return g.lockMem(noLock)
+}
+
+func (g *NvidiaGpu) lockGpu(l clockLock) error {
+	if l.open() {
+		if ret := g.symbols.DeviceResetGpuLockedClocks(g.handle); ret != SUCCESS {
+			return g.symbols.Error(ret)
+		}
+		g.gpuLock = noLock
+		return nil
+	}
+
+	if _, err := g.GetSupportedClocks(); err != nil {
+		return err
+	}
+	if len(g.gpuClocks) == 0 {
+		return fmt.Errorf("%w: no supported gpu clocks", gpu.ErrNotSupported)
+	}
+	l, lo, hi := snapLock(l, g.gpuClocks)
+	if l.open() {
+		return g.lockGpu(l)
+	}
+	if ret := g.symbols.DeviceSetGpuLockedClocks(g.handle, uint32(lo), uint32(hi)); ret != SUCCESS {
+		return g.symbols.Error(ret)
+	}
+	g.gpuLock = l
+	return nil
+}
+
+func (g *NvidiaGpu) lockMem(l clockLock) error {
+	if g.symbols.DeviceSetMemoryLockedClocks == nil || g.symbols.DeviceResetMemoryLockedClocks == nil {
+		return errMissing("nvmlDeviceSetMemoryLockedClocks")
+	}
+	if l.open() {
+		if ret := g.symbols.DeviceResetMemoryLockedClocks(g.handle); ret != SUCCESS {
+			return g.symbols.Error(ret)
+		}
+		g.memLock = noLock
+		return nil
+	}
+
+	t, err := g.GetSupportedClocks()
+	if err != nil {
+		return err
+	}
+	l, lo, hi := snapLock(l, t.Mem)
+	if l.open() {
+		return g.lockMem(l)
+	}
+	if ret := g.symbols.DeviceSetMemoryLockedClocks(g.handle, uint32(lo), uint32(hi)); ret != SUCCESS {
+		return g.symbols.Error(ret)
+	}
+	g.memLock = l
+	return nil
+}
+
+// snapLock moves the bounds of l onto table and returns the range to pass
+// to the driver. Bounds at or past the ends of the table, zero or negative
+// are open. A min above the max is lowered to it, as the driver is told,
+// and kept in wantMin for when the max is raised.
+func snapLock(l clockLock, table []int) (clockLock, int, int) {
+	l.known, l.wantMin = true, gpu.NO_VALUE
+	lo, hi := table[0], table[len(table)-1]
+	if l.min <= lo {
+		l.min = gpu.NO_VALUE
+	} else {
+		l.min = gpu.SnapUp(table, l.min)
+	}
+	if l.max <= 0 || l.max >= hi {
+		l.max = gpu.NO_VALUE
+	} else {
+		l.max = gpu.SnapDown(table, l.max)
+	}
+
+	min, max := lo, hi
+	if l.min != gpu.NO_VALUE {
+		min = l.min
+	}
+	if l.max != gpu.NO_VALUE {
+		max = l.max
+	}
+	if min > max {
+		l.wantMin, l.min, min = l.min, max, max
+	}
+	return l, min, max
+}
@@ -1,11 +1,10 @@
 package nvidia
 
 import (
-	"errors"
 	"fmt"
 	"nvtuner-go/internal/gpu"
 	"strings"
-	"unsafe"
+	"sync"
 )
 
 var _ gpu.Device = (*NvidiaGpu)(nil)
@@ -16,28 +15,51 @@ type NvidiaGpu struct {
 	index   int
 	name    string
 	uuid    string
+
+	fieldsMu sync.Mutex
+	fields   []FieldId               // telemetry fields the device answers
+	counters map[FieldId]counterRead // last read of counter fields, for rates
+
+	pcieMu sync.Mutex
+	pcie   pcieReading
+
+	capsOnce sync.Once
+	caps     gpu.Capabilities
+
+	clocksMu sync.Mutex
+	clocks   gpu.ClockTable // set once read successfully
+
+	lockMu    sync.Mutex
+	gpuLock   clockLock // what was last set through this handle
+	memLock   clockLock
+	gpuClocks []int // all supported graphics clocks, ascending
+
+	events *eventSet // the driver's, for Xids
 }
 
-func NewNvidiaGpu(handle Device, symbols *RawSymbols) *NvidiaGpu {
-	g := &NvidiaGpu{handle: handle, symbols: symbols}
+func NewNvidiaGpu(handle Device, symbols *RawSymbols) (*NvidiaGpu, error) {
+	g := &NvidiaGpu{handle: handle, symbols: symbols, gpuLock: unknownLock, memLock: unknownLock}
 
-	g.fetchIndex()
+	if err := g.fetchIndex(); err != nil {
+		return nil, err
+	}
 	g.fetchName()
 	g.fetchUUID()
 
-	return g
+	return g, nil
 }
 
 func (g *NvidiaGpu) GetIndex() int   { return g.index }
 func (g *NvidiaGpu) GetName() string { return g.name }
 func (g *NvidiaGpu) GetUUID() string { return g.uuid }
 
-func (g *NvidiaGpu) fetchIndex() {
+func (g *NvidiaGpu) fetchIndex() error {
 	var index uint32
 	if ret := g.symbols.DeviceGetIndex(g.handle, &index); ret != SUCCESS {
-		panic("Fatal: failed to fetch gpu index. check if your gpu is still connected.")
+		return fmt.Errorf("failed to fetch gpu index: %w", g.symbols.Error(ret))
 	}
 	g.index = int(index)
+	return nil
 }
 
 func (g *NvidiaGpu) fetchName() {
@@ -56,10 +78,38 @@ func (g *NvidiaGpu) fetchUUID() {
 	g.uuid = strings.TrimRight(string(buf[:]), "\x00")
 }
 
+func (g *NvidiaGpu) GetPciInfo() (gpu.PciInfo, error) {
+	if g.symbols.DeviceGetPciInfo_v3 == nil {
+		return gpu.PciInfo{}, errMissing("nvmlDeviceGetPciInfo_v3")
+	}
+
+	var pci PciInfo
+	if ret := g.symbols.DeviceGetPciInfo_v3(g.handle, &pci); ret != SUCCESS {
+		return gpu.PciInfo{}, g.symbols.Error(ret)
+	}
+	return gpu.PciInfo{
+		BusID:       strings.TrimRight(string(pci.BusId[:]), "\x00"),
+		DeviceID:    pci.PciDeviceId,
+		SubsystemID: pci.PciSubSystemId,
+	}, nil
+}
+
+func (g *NvidiaGpu) GetBoardPartNumber() (string, error) {
+	if g.symbols.DeviceGetBoardPartNumber == nil {
+		return "", errMissing("nvmlDeviceGetBoardPartNumber")
+	}
+
+	var buf [DEVICE_PART_NUMBER_BUFFER_SIZE]byte
+	if ret := g.symbols.DeviceGetBoardPartNumber(g.handle, &buf[0], DEVICE_PART_NUMBER_BUFFER_SIZE); ret != SUCCESS {
+		return "", g.symbols.Error(ret)
+	}
+	return strings.TrimRight(string(buf[:]), "\x00"), nil
+}
+
 func (g *NvidiaGpu) GetUtil() (int, int, error) {
 	var util Utilization
 	if ret := g.symbols.DeviceGetUtilizationRates(g.handle, &util); ret != SUCCESS {
-		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
+		return gpu.NO_VALUE, gpu.NO_VALUE, g.symbols.Error(ret)
 	}
 	return int(util.Gpu), int(util.Memory), nil
 }
@@ -67,7 +117,7 @@ func (g *NvidiaGpu) GetUtil() (int, int, error) {
 func (g *NvidiaGpu) GetClocks() (int, int, error) {
 	var gclk, mclk uint32
 	if ret := g.symbols.DeviceGetClockInfo(g.handle, CLOCK_GRAPHICS, &gclk); ret != SUCCESS {
-		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
+		return gpu.NO_VALUE, gpu.NO_VALUE, g.symbols.Error(ret)
 	}
 	if ret := g.symbols.DeviceGetClockInfo(g.handle, CLOCK_MEM, &mclk); ret != SUCCESS {
 		return int(gclk), gpu.NO_VALUE, nil
@@ -78,7 +128,7 @@ func (g *NvidiaGpu) GetClocks() (int, int, error) {
 func (g *NvidiaGpu) GetMemory() (int, int, int, error) {
 	var mem Memory
 	if ret := g.symbols.DeviceGetMemoryInfo(g.handle, &mem); ret != SUCCESS {
-		return gpu.NO_VALUE, gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
+		return gpu.NO_VALUE, gpu.NO_VALUE, gpu.NO_VALUE, g.symbols.Error(ret)
 	}
 	return int(mem.Total), int(mem.Free), int(mem.Used), nil
 }
@@ -86,7 +136,7 @@ func (g *NvidiaGpu) GetMemory() (int, int, int, error) {
 func (g *NvidiaGpu) GetPower() (int, error) {
 	var mw uint32
 	if ret := g.symbols.DeviceGetPowerUsage(g.handle, &mw); ret == SUCCESS {
-		return int(mw / 1000), nil
+		return int(mw), nil
 	}
 	return g.getPowerViaSample()
 }
@@ -100,30 +150,21 @@ func (g *NvidiaGpu) getPowerViaSample() (int, error) {
 
 	ret := g.symbols.DeviceGetSamples(g.handle, TOTAL_POWER_SAMPLES, 0, &sampleType, &sampleCount, &sample)
 	if ret != SUCCESS || sampleCount == 0 {
-		return gpu.NO_VALUE, fmt.Errorf("sampling failed: %s", g.symbols.StringFromReturn(ret))
-	}
-
-	var mw float64
-	switch sampleType {
-	case VALUE_TYPE_DOUBLE:
-		mw = *(*float64)(unsafe.Pointer(&sample.SampleValue.Data))
-	case VALUE_TYPE_UNSIGNED_INT:
-		mw = float64(*(*uint32)(unsafe.Pointer(&sample.SampleValue.Data)))
-	case VALUE_TYPE_UNSIGNED_LONG, VALUE_TYPE_UNSIGNED_LONG_LONG:
-		mw = float64(*(*uint64)(unsafe.Pointer(&sample.SampleValue.Data)))
-	default:
-		mw = float64(sample.SampleValue.AsUint())
+		return gpu.NO_VALUE, fmt.Errorf("sampling failed: %w", g.symbols.Error(ret))
 	}
 
-	return int(mw / 1000), nil
+	return int(sample.SampleValue.AsFloat(sampleType)), nil
 }
 
+// GetTemperature reads the GPU die sensor. NVML has no hotspot sensor,
+// neither in nvmlTemperatureSensors_t nor as a field id, so there is none
+// to report next to it.
 func (g *NvidiaGpu) GetTemperature() (int, error) {
 	// fallback
 	if g.symbols.DeviceGetTemperatureV == nil {
 		var temp uint32
 		if ret := g.symbols.DeviceGetTemperature(g.handle, TEMPERATURE_GPU, &temp); ret != SUCCESS {
-			return gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
+			return gpu.NO_VALUE, g.symbols.Error(ret)
 		}
 		return int(temp), nil
 	}
@@ -132,7 +173,7 @@ func (g *NvidiaGpu) GetTemperature() (int, error) {
 	temp.Version = VERSION_TEMPERATURE
 	temp.SensorType = TEMPERATURE_GPU
 	if ret := g.symbols.DeviceGetTemperatureV(g.handle, &temp); ret != SUCCESS {
-		return gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
+		return gpu.NO_VALUE, g.symbols.Error(ret)
 	}
 	return int(temp.Temperature), nil
 
@@ -141,7 +182,7 @@ func (g *NvidiaGpu) GetTemperature() (int, error) {
 func (g *NvidiaGpu) GetFanSpeed() (int, int, error) {
 	var percent uint32
 	if ret := g.symbols.DeviceGetFanSpeed(g.handle, &percent); ret != SUCCESS {
-		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
+		return gpu.NO_VALUE, gpu.NO_VALUE, g.symbols.Error(ret)
 	}
 
 	// error only if fan speed percent is not available
@@ -158,109 +199,41 @@ func (g *NvidiaGpu) GetFanSpeed() (int, int, error) {
 func (g *NvidiaGpu) GetPl() (int, error) {
 	var mw uint32
 	if ret := g.symbols.DeviceGetEnforcedPowerLimit(g.handle, &mw); ret != SUCCESS {
-		return gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
+		return gpu.NO_VALUE, g.symbols.Error(ret)
 	}
-	return int(mw / 1000), nil
+	return int(mw), nil
 }
 
 func (g *NvidiaGpu) GetPlDefault() (int, error) {
 	var mw uint32
 	if ret := g.symbols.DeviceGetPowerManagementDefaultLimit(g.handle, &mw); ret != SUCCESS {
-		return gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
+		return gpu.NO_VALUE, g.symbols.Error(ret)
 	}
-	return int(mw / 1000), nil
+	return int(mw), nil
 }
 
 func (g *NvidiaGpu) GetCoGpu() (int, error) {
-	// fallback
-	if g.symbols.DeviceGetClockOffsets == nil {
-		var co int32
-		if ret := g.symbols.DeviceGetGpcClkVfOffset(g.handle, &co); ret != SUCCESS {
-			return gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
-		}
-		return int(co), nil
-	}
-
-	var co ClockOffset
-	co.Version = VERSION_CLOCK_OFFSET
-	co.Type = CLOCK_GRAPHICS
-	co.Pstate = PSTATE_0
-	if ret := g.symbols.DeviceGetClockOffsets(g.handle, &co); ret != SUCCESS {
-		return gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
-	}
-	return int(co.ClockOffsetMHz), nil
+	return g.GetCoGpuAt(0)
 }
 
 func (g *NvidiaGpu) GetCoMem() (int, error) {
-	// fallback
-	if g.symbols.DeviceGetClockOffsets == nil {
-		var co int32
-		if ret := g.symbols.DeviceGetMemClkVfOffset(g.handle, &co); ret != SUCCESS {
-			return gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
-		}
-		return int(co), nil
-	}
-
-	var co ClockOffset
-	co.Version = VERSION_CLOCK_OFFSET
-	co.Type = CLOCK_MEM
-	co.Pstate = PSTATE_0
-	if ret := g.symbols.DeviceGetClockOffsets(g.handle, &co); ret != SUCCESS {
-		return gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
-	}
-	return int(co.ClockOffsetMHz), nil
-}
-
-func (g *NvidiaGpu) GetClGpu() (int, error) {
-	return gpu.NO_VALUE, fmt.Errorf("not supported by nvidia yet")
+	return g.GetCoMemAt(0)
 }
 
 func (g *NvidiaGpu) GetPlLim() (int, int, error) {
 	var min, max uint32
 	if ret := g.symbols.DeviceGetPowerManagementLimitConstraints(g.handle, &min, &max); ret != SUCCESS {
-		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
+		return gpu.NO_VALUE, gpu.NO_VALUE, g.symbols.Error(ret)
 	}
-	return int(min / 1000), int(max / 1000), nil
+	return int(min), int(max), nil
 }
 
 func (g *NvidiaGpu) GetCoLimGpu() (int, int, error) {
-	// fallback
-	if g.symbols.DeviceGetClockOffsets == nil {
-		var min, max int32
-		if ret := g.symbols.DeviceGetGpcClkMinMaxVfOffset(g.handle, &min, &max); ret != SUCCESS {
-			return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
-		}
-		return int(min), int(max), nil
-	}
-
-	var co ClockOffset
-	co.Version = VERSION_CLOCK_OFFSET
-	co.Type = CLOCK_GRAPHICS
-	co.Pstate = PSTATE_0
-	if ret := g.symbols.DeviceGetClockOffsets(g.handle, &co); ret != SUCCESS {
-		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
-	}
-	return int(co.MinClockOffsetMHz), int(co.MaxClockOffsetMHz), nil
+	return g.GetCoLimGpuAt(0)
 }
 
 func (g *NvidiaGpu) GetCoLimMem() (int, int, error) {
-	// fallback
-	if g.symbols.DeviceGetClockOffsets == nil {
-		var min, max int32
-		if ret := g.symbols.DeviceGetMemClkMinMaxVfOffset(g.handle, &min, &max); ret != SUCCESS {
-			return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
-		}
-		return int(min), int(max), nil
-	}
-
-	var co ClockOffset
-	co.Version = VERSION_CLOCK_OFFSET
-	co.Type = CLOCK_MEM
-	co.Pstate = PSTATE_0
-	if ret := g.symbols.DeviceGetClockOffsets(g.handle, &co); ret != SUCCESS {
-		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf(g.symbols.StringFromReturn(ret))
-	}
-	return int(co.MinClockOffsetMHz), int(co.MaxClockOffsetMHz), nil
+	return g.GetCoLimMemAt(0)
 }
 
 func (g *NvidiaGpu) GetClLimGpu() (int, int, error) {
@@ -277,66 +250,27 @@ func (g *NvidiaGpu) CanSetPl() bool {
 		g.symbols.DeviceGetPowerManagementLimit(g.handle, &limit) == SUCCESS
 }
 
-func (g *NvidiaGpu) SetPl(watt int) error {
+func (g *NvidiaGpu) SetPl(mw int) error {
 	if !g.CanSetPl() {
-		return errors.New("controlled by vbios/hardware")
+		return fmt.Errorf("%w: controlled by vbios/hardware", gpu.ErrNotSupported)
 	}
-	if ret := g.symbols.DeviceSetPowerManagementLimit(g.handle, uint32(watt*1000)); ret != SUCCESS {
-		return fmt.Errorf(g.symbols.StringFromReturn(ret))
+	if ret := g.symbols.DeviceSetPowerManagementLimit(g.handle, uint32(mw)); ret != SUCCESS {
+		return g.symbols.Error(ret)
 	}
 	return nil
 }
 
 func (g *NvidiaGpu) SetCoGpu(mhz int) error {
-	// fallback
-	if g.symbols.DeviceSetClockOffsets == nil {
-		if ret := g.symbols.DeviceSetGpcClkVfOffset(g.handle, int32(mhz)); ret != SUCCESS {
-			return fmt.Errorf(g.symbols.StringFromReturn(ret))
-		}
-		return nil
-	}
-
-	var co ClockOffset
-	co.Version = VERSION_CLOCK_OFFSET
-	co.Type = CLOCK_GRAPHICS
-	co.Pstate = PSTATE_0
-	co.ClockOffsetMHz = int32(mhz)
-	if ret := g.symbols.DeviceSetClockOffsets(g.handle, &co); ret != SUCCESS {
-		return fmt.Errorf(g.symbols.StringFromReturn(ret))
-	}
-	return nil
+	return g.SetCoGpuAt(0, mhz)
 }
 
 func (g *NvidiaGpu) SetCoMem(mhz int) error {
-	// fallback
-	if g.symbols.DeviceSetClockOffsets == nil {
-		if ret := g.symbols.DeviceSetMemClkVfOffset(g.handle, int32(mhz)); ret != SUCCESS {
-			return fmt.Errorf(g.symbols.StringFromReturn(ret))
-		}
-		return nil
-	}
-
-	var co ClockOffset
-	co.Version = VERSION_CLOCK_OFFSET
-	co.Type = CLOCK_MEM
-	co.Pstate = PSTATE_0
-	co.ClockOffsetMHz = int32(mhz)
-	if ret := g.symbols.DeviceSetClockOffsets(g.handle, &co); ret != SUCCESS {
-		return fmt.Errorf(g.symbols.StringFromReturn(ret))
-	}
-	return nil
-}
-
-func (g *NvidiaGpu) SetClGpu(mhz int) error {
-	if ret := g.symbols.DeviceSetGpuLockedClocks(g.handle, 0, uint32(mhz)); ret != SUCCESS {
-		return fmt.Errorf(g.symbols.StringFromReturn(ret))
-	}
-	return nil
+	return g.SetCoMemAt(0, mhz)
 }
 
 func (g *NvidiaGpu) ResetPl() error {
 	if !g.CanSetPl() {
-		return errors.New("controlled by vbios/hardware")
+		return fmt.Errorf("%w: controlled by vbios/hardware", gpu.ErrNotSupported)
 	}
 	defaultPl, err := g.GetPlDefault()
 	if err != nil {
@@ -353,18 +287,11 @@ func (g *NvidiaGpu) ResetCoMem() error {
 	return g.SetCoMem(0)
 }
 
-func (g *NvidiaGpu) ResetClGpu() error {
-	if ret := g.symbols.DeviceResetGpuLockedClocks(g.handle); ret != SUCCESS {
-		return fmt.Errorf(g.symbols.StringFromReturn(ret))
-	}
-	return nil
-}
-
 func (g *NvidiaGpu) getSupportedMemClocks() ([]int, error) {
 	var count uint32
 	ret := g.symbols.DeviceGetSupportedMemoryClocks(g.handle, &count, nil)
 	if ret != SUCCESS && ret != ERROR_INSUFFICIENT_SIZE {
-		return nil, fmt.Errorf("failed to get supported mem clock count: %s", g.symbols.StringFromReturn(ret))
+		return nil, fmt.Errorf("failed to get supported mem clock count: %w", g.symbols.Error(ret))
 	}
 
 	if count == 0 {
@@ -374,7 +301,7 @@ func (g *NvidiaGpu) getSupportedMemClocks() ([]int, error) {
 	clocks := make([]uint32, count)
 	ret = g.symbols.DeviceGetSupportedMemoryClocks(g.handle, &count, &clocks[0])
 	if ret != SUCCESS {
-		return nil, fmt.Errorf("failed to fetch supported mem clocks: %s", g.symbols.StringFromReturn(ret))
+		return nil, fmt.Errorf("failed to fetch supported mem clocks: %w", g.symbols.Error(ret))
 	}
 
 	res := make([]int, count)
@@ -388,7 +315,7 @@ func (g *NvidiaGpu) getSupportedGpuClocks(memClockMHz int) ([]int, error) {
 	var count uint32
 	ret := g.symbols.DeviceGetSupportedGraphicsClocks(g.handle, uint32(memClockMHz), &count, nil)
 	if ret != SUCCESS && ret != ERROR_INSUFFICIENT_SIZE {
-		return nil, fmt.Errorf("failed to get gpu clock count: %s", g.symbols.StringFromReturn(ret))
+		return nil, fmt.Errorf("failed to get gpu clock count: %w", g.symbols.Error(ret))
 	}
 
 	if count == 0 {
@@ -398,7 +325,7 @@ func (g *NvidiaGpu) getSupportedGpuClocks(memClockMHz int) ([]int, error) {
 	clocks := make([]uint32, count)
 	ret = g.symbols.DeviceGetSupportedGraphicsClocks(g.handle, uint32(memClockMHz), &count, &clocks[0])
 	if ret != SUCCESS {
-		return nil, fmt.Errorf("failed to fetch gpu clocks: %s", g.symbols.StringFromReturn(ret))
+		return nil, fmt.Errorf("failed to fetch gpu clocks: %w", g.symbols.Error(ret))
 	}
 
 	res := make([]int, count)
@@ -413,7 +340,7 @@ func (g *NvidiaGpu) getClLimGpuV1() (int, int, error) {
 
 	var max uint32
 	if ret := g.symbols.DeviceGetMaxClockInfo(g.handle, CLOCK_GRAPHICS, &max); ret != SUCCESS {
-		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf("failed to get max clock: %s", g.symbols.StringFromReturn(ret))
+		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf("failed to get max clock: %w", g.symbols.Error(ret))
 	}
 	co, err := g.GetCoGpu()
 	if err != nil {
@@ -425,52 +352,14 @@ func (g *NvidiaGpu) getClLimGpuV1() (int, int, error) {
 func (g *NvidiaGpu) getClLimGpuV2() (int, int, error) {
 	// see also: nvidia-smi -q -d SUPPORTED_CLOCKS
 
-	memClocks, err := g.getSupportedMemClocks()
-	if err != nil {
-		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf("failed to get supported mem clocks: %w", err)
-	}
-	if len(memClocks) == 0 {
-		return gpu.NO_VALUE, gpu.NO_VALUE, errors.New("no supported mem clocks")
-	}
-
-	minMem := memClocks[0]
-	maxMem := memClocks[0]
-	for _, m := range memClocks {
-		if m < minMem {
-			minMem = m
-		}
-		if m > maxMem {
-			maxMem = m
-		}
-	}
-
-	minGpuClocks, err := g.getSupportedGpuClocks(minMem)
+	t, err := g.GetSupportedClocks()
 	if err != nil {
-		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf("failed to get gpu clocks for min mem %d: %v", minMem, err)
+		return gpu.NO_VALUE, gpu.NO_VALUE, err
 	}
-	if len(minGpuClocks) == 0 {
-		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf("no gpu clocks found for min mem %d", minMem)
+	minMem, maxMem := t.Mem[0], t.Mem[len(t.Mem)-1]
+	minGpu, maxGpu := t.Gpu[minMem], t.Gpu[maxMem]
+	if len(minGpu) == 0 || len(maxGpu) == 0 {
+		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf("%w: no gpu clocks found for mem %d-%d", gpu.ErrNotSupported, minMem, maxMem)
 	}
-	maxGpuClocks, err := g.getSupportedGpuClocks(maxMem)
-	if err != nil {
-		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf("failed to get gpu clocks for max mem %d: %v", maxMem, err)
-	}
-	if len(maxGpuClocks) == 0 {
-		return gpu.NO_VALUE, gpu.NO_VALUE, fmt.Errorf("no gpu clocks found for max mem %d", maxMem)
-	}
-
-	minGpu := minGpuClocks[0]
-	for _, g := range minGpuClocks {
-		if g < minGpu {
-			minGpu = g
-		}
-	}
-	maxGpu := maxGpuClocks[0]
-	for _, g := range maxGpuClocks {
-		if g > maxGpu {
-			maxGpu = g
-		}
-	}
-
-	return minGpu, maxGpu, nil
+	return minGpu[0], maxGpu[len(maxGpu)-1], nil
 }
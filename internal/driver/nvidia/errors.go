@@ -0,0 +1,48 @@
+package nvidia
+
+import (
+	"fmt"
+
+	"nvtuner-go/internal/gpu"
+)
+
+// Error keeps the NVML return code and unwraps to the matching gpu sentinel
+// error, if any.
+type Error struct {
+	Code Return
+	msg  string
+	kind error
+}
+
+func (e *Error) Error() string { return e.msg }
+func (e *Error) Unwrap() error { return e.kind }
+
+var errorKinds = map[Return]error{
+	ERROR_NOT_SUPPORTED:             gpu.ErrNotSupported,
+	ERROR_FUNCTION_NOT_FOUND:        gpu.ErrNotSupported,
+	ERROR_VGPU_ECC_NOT_SUPPORTED:    gpu.ErrNotSupported,
+	ERROR_NO_PERMISSION:             gpu.ErrNoPermission,
+	ERROR_INVALID_ARGUMENT:          gpu.ErrInvalidArgument,
+	ERROR_ARGUMENT_VERSION_MISMATCH: gpu.ErrInvalidArgument,
+	ERROR_FREQ_NOT_SUPPORTED:        gpu.ErrInvalidArgument,
+	ERROR_GPU_IS_LOST:               gpu.ErrGpuLost,
+	ERROR_GPU_NOT_FOUND:             gpu.ErrGpuLost,
+	ERROR_UNINITIALIZED:             gpu.ErrDriverNotLoaded,
+	ERROR_DRIVER_NOT_LOADED:         gpu.ErrDriverNotLoaded,
+	ERROR_LIBRARY_NOT_FOUND:         gpu.ErrDriverNotLoaded,
+	ERROR_LIB_RM_VERSION_MISMATCH:   gpu.ErrDriverNotLoaded,
+	ERROR_TIMEOUT:                   gpu.ErrTimeout,
+	ERROR_IN_USE:                    gpu.ErrInUse,
+	ERROR_RESET_REQUIRED:            gpu.ErrResetRequired,
+	ERROR_INSUFFICIENT_POWER:        gpu.ErrInsufficientPower,
+}
+
+// Error converts a failed return code into an error.
+func (s *RawSymbols) Error(ret Return) error {
+	return &Error{Code: ret, msg: s.StringFromReturn(ret), kind: errorKinds[ret]}
+}
+
+// errMissing reports a function the loaded library doesn't export.
+func errMissing(fn string) error {
+	return fmt.Errorf("%s: %w", fn, gpu.ErrNotSupported)
+}
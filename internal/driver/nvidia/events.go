@@ -0,0 +1,125 @@
+package nvidia
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"nvtuner-go/internal/gpu"
+)
+
+// listenedEvents are registered for every device, as far as it supports them.
+const listenedEvents = EVENT_TYPE_CLOCK | EVENT_TYPE_POWER_SOURCE_CHANGE | EVENT_TYPE_XID_CRITICAL_ERROR |
+	EVENT_TYPE_SINGLE_BIT_ECC_ERROR | EVENT_TYPE_DOUBLE_BIT_ECC_ERROR
+
+const xidKeep = 32 // per device
+
+// eventSet is the driver's one NVML event set. Waiting holds the read lock
+// so the set is not freed under a waiter.
+type eventSet struct {
+	s *RawSymbols
+
+	mu   sync.RWMutex
+	set  EventSet
+	ok   bool
+	devs map[Device]*NvidiaGpu // registered handles
+
+	xidMu sync.Mutex
+	xids  map[string][]gpu.Xid // by UUID, kept across re-inits
+}
+
+func newEventSet(s *RawSymbols) *eventSet {
+	return &eventSet{s: s, xids: make(map[string][]gpu.Xid)}
+}
+
+// open creates the set after nvmlInit; without event support it stays
+// closed and waiting fails.
+func (es *eventSet) open() {
+	s := es.s
+	if s.EventSetCreate == nil || s.EventSetFree == nil || s.EventSetWait_v2 == nil || s.DeviceRegisterEvents == nil {
+		return
+	}
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.ok = s.EventSetCreate(&es.set) == SUCCESS
+	es.devs = make(map[Device]*NvidiaGpu)
+}
+
+// close frees the set; call it before nvmlShutdown.
+func (es *eventSet) close() {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.ok {
+		es.s.EventSetFree(es.set)
+	}
+	es.ok, es.devs = false, nil
+}
+
+// register adds the events a device supports; registering twice is a no-op.
+func (es *eventSet) register(g *NvidiaGpu) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if !es.ok {
+		return
+	}
+	if _, ok := es.devs[g.handle]; ok {
+		return
+	}
+	types := listenedEvents
+	if es.s.DeviceGetSupportedEventTypes != nil {
+		var supported uint64
+		if es.s.DeviceGetSupportedEventTypes(g.handle, &supported) == SUCCESS {
+			types &= supported
+		}
+	}
+	if types != 0 && es.s.DeviceRegisterEvents(g.handle, types, es.set) == SUCCESS {
+		es.devs[g.handle] = g
+	}
+}
+
+func (es *eventSet) wait(timeout time.Duration) (gpu.Event, error) {
+	es.mu.RLock()
+	defer es.mu.RUnlock()
+	if !es.ok {
+		return gpu.Event{}, fmt.Errorf("%w: no event set", gpu.ErrNotSupported)
+	}
+
+	var data EventData
+	if ret := es.s.EventSetWait_v2(es.set, &data, uint32(timeout/time.Millisecond)); ret != SUCCESS {
+		return gpu.Event{}, es.s.Error(ret)
+	}
+	g, ok := es.devs[data.Device]
+	if !ok {
+		return gpu.Event{}, fmt.Errorf("%w: event of unknown device", gpu.ErrTimeout) // like none
+	}
+
+	e := gpu.Event{Index: g.index, UUID: g.uuid, Time: time.Now(), Data: data.EventData}
+	switch {
+	case data.EventType&EVENT_TYPE_XID_CRITICAL_ERROR != 0:
+		e.Kind = gpu.EventXid
+		es.addXid(g.uuid, gpu.Xid{Time: e.Time, Code: int(data.EventData)})
+	case data.EventType&EVENT_TYPE_DOUBLE_BIT_ECC_ERROR != 0:
+		e.Kind = gpu.EventEccDoubleBit
+	case data.EventType&EVENT_TYPE_SINGLE_BIT_ECC_ERROR != 0:
+		e.Kind = gpu.EventEccSingleBit
+	case data.EventType&EVENT_TYPE_POWER_SOURCE_CHANGE != 0:
+		e.Kind = gpu.EventPowerSource
+	default:
+		e.Kind = gpu.EventClock
+	}
+	return e, nil
+}
+
+func (es *eventSet) addXid(uuid string, x gpu.Xid) {
+	es.xidMu.Lock()
+	defer es.xidMu.Unlock()
+	xids := append(es.xids[uuid], x)
+	es.xids[uuid] = xids[max(0, len(xids)-xidKeep):]
+}
+
+func (es *eventSet) getXids(uuid string) []gpu.Xid {
+	es.xidMu.Lock()
+	defer es.xidMu.Unlock()
+	return slices.Clone(es.xids[uuid])
+}
@@ -0,0 +1,84 @@
+package nvidia
+
+import (
+	"nvtuner-go/internal/gpu"
+)
+
+// GetHealthReport reads every memory error counter the device has. It only
+// fails if the device is lost.
+func (g *NvidiaGpu) GetHealthReport() (gpu.HealthReport, error) {
+	s := g.symbols
+	var r gpu.HealthReport
+
+	cur, pending, err := g.getEccMode()
+	switch {
+	case err == nil:
+		r.Ecc, r.EccPending = gpu.FlagOf(cur), gpu.FlagOf(pending)
+	case gpu.IsLost(err):
+		return r, err
+	}
+	if r.Ecc.On() {
+		r.EccCounts.VolatileCorrected = gpu.UnitNone.Read(g.getEccErrors(MEMORY_ERROR_TYPE_CORRECTED, VOLATILE_ECC))
+		r.EccCounts.VolatileUncorrected = gpu.UnitNone.Read(g.getEccErrors(MEMORY_ERROR_TYPE_UNCORRECTED, VOLATILE_ECC))
+		r.EccCounts.AggregateCorrected = gpu.UnitNone.Read(g.getEccErrors(MEMORY_ERROR_TYPE_CORRECTED, AGGREGATE_ECC))
+		r.EccCounts.AggregateUncorrected = gpu.UnitNone.Read(g.getEccErrors(MEMORY_ERROR_TYPE_UNCORRECTED, AGGREGATE_ECC))
+	}
+
+	// page retirement, before Ampere
+	r.RetiredSbe = gpu.UnitNone.Read(g.getRetiredPages(PAGE_RETIREMENT_CAUSE_MULTIPLE_SINGLE_BIT_ECC_ERRORS))
+	r.RetiredDbe = gpu.UnitNone.Read(g.getRetiredPages(PAGE_RETIREMENT_CAUSE_DOUBLE_BIT_ECC_ERROR))
+	if s.DeviceGetRetiredPagesPendingStatus != nil {
+		var pending EnableState
+		if s.DeviceGetRetiredPagesPendingStatus(g.handle, &pending) == SUCCESS {
+			r.RetiredPending = gpu.FlagOf(pending == FEATURE_ENABLED)
+		}
+	}
+
+	// row remapping, Ampere and later
+	if s.DeviceGetRemappedRows != nil {
+		var corr, unc, pending, failed uint32
+		if s.DeviceGetRemappedRows(g.handle, &corr, &unc, &pending, &failed) == SUCCESS {
+			r.RemappedCorrectable, r.RemappedUncorrectable = gpu.UnitNone.Of(int(corr)), gpu.UnitNone.Of(int(unc))
+			r.RemapPending, r.RemapFailed = gpu.FlagOf(pending != 0), gpu.FlagOf(failed != 0)
+		}
+	}
+
+	if g.events != nil {
+		r.Xids = g.events.getXids(g.uuid)
+	}
+	return r, nil
+}
+
+func (g *NvidiaGpu) getEccMode() (bool, bool, error) {
+	if g.symbols.DeviceGetEccMode == nil {
+		return false, false, errMissing("nvmlDeviceGetEccMode")
+	}
+	var cur, pending EnableState
+	if ret := g.symbols.DeviceGetEccMode(g.handle, &cur, &pending); ret != SUCCESS {
+		return false, false, g.symbols.Error(ret)
+	}
+	return cur == FEATURE_ENABLED, pending == FEATURE_ENABLED, nil
+}
+
+func (g *NvidiaGpu) getEccErrors(typ MemoryErrorType, counter EccCounterType) (int, error) {
+	if g.symbols.DeviceGetTotalEccErrors == nil {
+		return gpu.NO_VALUE, errMissing("nvmlDeviceGetTotalEccErrors")
+	}
+	var n uint64
+	if ret := g.symbols.DeviceGetTotalEccErrors(g.handle, typ, counter, &n); ret != SUCCESS {
+		return gpu.NO_VALUE, g.symbols.Error(ret)
+	}
+	return int(n), nil
+}
+
+// getRetiredPages only counts the pages, a zero count asks for the size.
+func (g *NvidiaGpu) getRetiredPages(cause PageRetirementCause) (int, error) {
+	if g.symbols.DeviceGetRetiredPages == nil {
+		return gpu.NO_VALUE, errMissing("nvmlDeviceGetRetiredPages")
+	}
+	var n uint32
+	if ret := g.symbols.DeviceGetRetiredPages(g.handle, cause, &n, nil); ret != SUCCESS && ret != ERROR_INSUFFICIENT_SIZE {
+		return gpu.NO_VALUE, g.symbols.Error(ret)
+	}
+	return int(n), nil
+}
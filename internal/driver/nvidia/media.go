@@ -0,0 +1,95 @@
+package nvidia
+
+import (
+	"nvtuner-go/internal/gpu"
+)
+
+// GetMediaStats reads what the media engines report; engines a device
+// lacks stay invalid. It only fails if the device is lost.
+func (g *NvidiaGpu) GetMediaStats() (gpu.MediaStats, error) {
+	s := g.symbols
+	m := gpu.NoMedia
+
+	util, err := g.getEngineUtil(s.DeviceGetEncoderUtilization, "nvmlDeviceGetEncoderUtilization")
+	if gpu.IsLost(err) {
+		return m, err
+	}
+	m.UtilEnc = gpu.UnitPercent.Read(util, err)
+	m.UtilDec = gpu.UnitPercent.Read(g.getEngineUtil(s.DeviceGetDecoderUtilization, "nvmlDeviceGetDecoderUtilization"))
+	m.UtilJpeg = gpu.UnitPercent.Read(g.getEngineUtil(s.DeviceGetJpgUtilization, "nvmlDeviceGetJpgUtilization"))
+	m.UtilOfa = gpu.UnitPercent.Read(g.getEngineUtil(s.DeviceGetOfaUtilization, "nvmlDeviceGetOfaUtilization"))
+
+	if s.DeviceGetEncoderStats != nil {
+		var n, fps, lat uint32
+		if s.DeviceGetEncoderStats(g.handle, &n, &fps, &lat) == SUCCESS {
+			m.EncSessions, m.EncFps, m.EncLatency = gpu.UnitNone.Of(int(n)), gpu.UnitNone.Of(int(fps)), gpu.UnitMicroSecond.Of(int(lat))
+		}
+	}
+	if s.DeviceGetFBCStats != nil {
+		var st FBCStats
+		if s.DeviceGetFBCStats(g.handle, &st) == SUCCESS {
+			m.FbcSessions, m.FbcFps = gpu.UnitNone.Of(int(st.SessionsCount)), gpu.UnitNone.Of(int(st.AverageFPS))
+			m.FbcLatency = gpu.UnitMicroSecond.Of(int(st.AverageLatency))
+		}
+	}
+	return m, nil
+}
+
+// getEngineUtil reads one engine's utilization over the driver's last
+// sampling period.
+func (g *NvidiaGpu) getEngineUtil(fn func(Device, *uint32, *uint32) Return, name string) (int, error) {
+	if fn == nil {
+		return gpu.NO_VALUE, errMissing(name)
+	}
+	var util, periodUs uint32
+	if ret := fn(g.handle, &util, &periodUs); ret != SUCCESS {
+		return gpu.NO_VALUE, g.symbols.Error(ret)
+	}
+	return int(util), nil
+}
+
+func (g *NvidiaGpu) GetEncoderSessions() ([]gpu.EncoderSession, error) {
+	if g.symbols.DeviceGetEncoderSessions == nil {
+		return nil, errMissing("nvmlDeviceGetEncoderSessions")
+	}
+	var n uint32
+	if ret := g.symbols.DeviceGetEncoderSessions(g.handle, &n, nil); ret != SUCCESS && ret != ERROR_INSUFFICIENT_SIZE {
+		return nil, g.symbols.Error(ret)
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	n += 4 // room for sessions started since counting
+	infos := make([]EncoderSessionInfo, n)
+	if ret := g.symbols.DeviceGetEncoderSessions(g.handle, &n, &infos[0]); ret != SUCCESS {
+		return nil, g.symbols.Error(ret)
+	}
+	infos = infos[:n]
+
+	res := make([]gpu.EncoderSession, 0, len(infos))
+	for _, in := range infos {
+		res = append(res, gpu.EncoderSession{
+			ID:      int(in.SessionId),
+			Pid:     int(in.Pid),
+			Codec:   encoderName(in.CodecType),
+			Width:   int(in.HResolution),
+			Height:  int(in.VResolution),
+			Fps:     int(in.AverageFps),
+			Latency: int(in.AverageLatency),
+		})
+	}
+	return res, nil
+}
+
+func encoderName(t EncoderType) string {
+	switch t {
+	case ENCODER_QUERY_H264:
+		return "H.264"
+	case ENCODER_QUERY_HEVC:
+		return "HEVC"
+	case ENCODER_QUERY_AV1:
+		return "AV1"
+	default:
+		return "unknown"
+	}
+}
@@ -4,30 +4,38 @@ import (
 	"fmt"
 	"nvtuner-go/internal/gpu"
 	"strings"
+	"time"
 )
 
 var _ gpu.Manager = (*NvidiaDriver)(nil)
+var _ gpu.EventWaiter = (*NvidiaDriver)(nil)
 
 type NvidiaDriver struct {
-	s *RawSymbols
+	s      *RawSymbols
+	events *eventSet
 }
 
 func New() (*NvidiaDriver, error) {
 	s, err := NewRawSymbols()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", gpu.ErrDriverNotLoaded, err)
 	}
-	return &NvidiaDriver{s: s}, nil
+	return &NvidiaDriver{s: s, events: newEventSet(s)}, nil
 }
 
 func (d *NvidiaDriver) Init() error {
+	if d.s.Init_v2 == nil {
+		return errMissing("nvmlInit_v2")
+	}
 	if ret := d.s.Init_v2(); ret != SUCCESS {
-		return fmt.Errorf("nvml init failed: %s", d.s.StringFromReturn(ret))
+		return fmt.Errorf("nvml init failed: %w", d.s.Error(ret))
 	}
+	d.events.open()
 	return nil
 }
 
 func (d *NvidiaDriver) Shutdown() error {
+	d.events.close()
 	if d.s.Shutdown != nil {
 		d.s.Shutdown()
 	}
@@ -57,7 +65,7 @@ func (d *NvidiaDriver) GetDriverVersion() string {
 func (d *NvidiaDriver) Devices() ([]gpu.Device, error) {
 	var count uint32
 	if ret := d.s.DeviceGetCount_v2(&count); ret != SUCCESS {
-		return nil, fmt.Errorf("get device count failed: %s", d.s.StringFromReturn(ret))
+		return nil, fmt.Errorf("get device count failed: %w", d.s.Error(ret))
 	}
 
 	var res []gpu.Device
@@ -67,11 +75,19 @@ func (d *NvidiaDriver) Devices() ([]gpu.Device, error) {
 			continue
 		}
 
-		g := NewNvidiaGpu(handle, d.s)
-		if g.index != int(i) {
-			panic("fatal: gpu index mismatch")
+		g, err := NewNvidiaGpu(handle, d.s)
+		if err != nil {
+			continue // fell off the bus between count and handle
 		}
+		g.events = d.events
+		d.events.register(g)
 		res = append(res, g)
 	}
 	return res, nil
 }
+
+// WaitEvent waits for an event of a device returned by Devices. Xids are
+// only recorded for health reports while someone waits, see gpu.Listener.
+func (d *NvidiaDriver) WaitEvent(timeout time.Duration) (gpu.Event, error) {
+	return d.events.wait(timeout)
+}
@@ -1,7 +1,6 @@
 package nvidia
 
 import (
-	"bytes"
 	"fmt"
 	"nvtuner-go/internal/libloader"
 	"unsafe"
@@ -20,7 +19,7 @@ type RawSymbols struct {
 	SystemGetNVMLVersion       func(buffer *byte, length uint32) Return // NVML_SYSTEM_NVML_VERSION_BUFFER_SIZE=80
 	SystemGetCudaDriverVersion func(version *int32) Return
 	Shutdown                   func() Return
-	ErrorString                func(result Return) uintptr
+	ErrorString                func(result Return) string // purego copies the C string
 
 	// find devices
 	DeviceGetCount_v2         func(count *uint32) Return
@@ -28,19 +27,56 @@ type RawSymbols struct {
 	DeviceGetIndex            func(device Device, index *uint32) Return
 	DeviceGetUUID             func(device Device, buffer *byte, length uint32) Return // NVML_DEVICE_UUID_BUFFER_SIZE=80
 	DeviceGetName             func(device Device, buffer *byte, length uint32) Return // NVML_DEVICE_NAME_BUFFER_SIZE=64
+	DeviceGetPciInfo_v3       func(device Device, pci *PciInfo) Return
+	DeviceGetBoardPartNumber  func(device Device, buffer *byte, length uint32) Return // NVML_DEVICE_PART_NUMBER_BUFFER_SIZE=80
 
 	// monitor
-	DeviceGetUtilizationRates          func(device Device, util *Utilization) Return
-	DeviceGetMemoryInfo                func(device Device, memory *Memory) Return
-	DeviceGetClockInfo                 func(device Device, clockType ClockType, clock *uint32) Return
-	DeviceGetPowerUsage                func(device Device, power *uint32) Return
-	DeviceGetEnforcedPowerLimit        func(device Device, limit *uint32) Return
-	DeviceGetTemperature               func(device Device, sensor TemperatureSensors, temp *uint32) Return
-	DeviceGetTemperatureV              func(device Device, info *Temperature) Return
-	DeviceGetFanSpeed                  func(device Device, speed *uint32) Return
-	DeviceGetFanSpeedRPM               func(device Device, info *FanSpeedInfo) Return
-	DeviceGetSamples                   func(device Device, samplingType SamplingType, lastSeen uint64, valType *ValueType, count *uint32, samples *Sample) Return
-	DeviceGetCurrentClocksEventReasons func(device Device, reasons *uint64) Return
+	DeviceGetUtilizationRates           func(device Device, util *Utilization) Return
+	DeviceGetMemoryInfo                 func(device Device, memory *Memory) Return
+	DeviceGetClockInfo                  func(device Device, clockType ClockType, clock *uint32) Return
+	DeviceGetPowerUsage                 func(device Device, power *uint32) Return
+	DeviceGetTotalEnergyConsumption     func(device Device, energy *uint64) Return // mJ
+	DeviceGetEnforcedPowerLimit         func(device Device, limit *uint32) Return
+	DeviceGetTemperature                func(device Device, sensor TemperatureSensors, temp *uint32) Return
+	DeviceGetTemperatureV               func(device Device, info *Temperature) Return
+	DeviceGetTemperatureThreshold       func(device Device, threshold TemperatureThresholds, temp *uint32) Return
+	DeviceGetFanSpeed                   func(device Device, speed *uint32) Return
+	DeviceGetFanSpeedRPM                func(device Device, info *FanSpeedInfo) Return
+	DeviceGetSamples                    func(device Device, samplingType SamplingType, lastSeen uint64, valType *ValueType, count *uint32, samples *Sample) Return
+	DeviceGetCurrentClocksEventReasons  func(device Device, reasons *uint64) Return
+	DeviceGetViolationStatus            func(device Device, policy PerfPolicyType, time *ViolationTime) Return
+	DeviceGetFieldValues                func(device Device, valuesCount int32, values *FieldValue) Return
+	DeviceGetPerformanceState           func(device Device, pstate *Pstates) Return
+	DeviceGetSupportedPerformanceStates func(device Device, pstates *Pstates, size uint32) Return // MAX_GPU_PERF_PSTATES
+	DeviceGetCurrPcieLinkGeneration     func(device Device, gen *uint32) Return
+	DeviceGetMaxPcieLinkGeneration      func(device Device, gen *uint32) Return
+	DeviceGetCurrPcieLinkWidth          func(device Device, width *uint32) Return
+	DeviceGetMaxPcieLinkWidth           func(device Device, width *uint32) Return
+	DeviceGetPcieThroughput             func(device Device, counter PcieUtilCounter, kbps *uint32) Return
+	DeviceGetPcieReplayCounter          func(device Device, count *uint32) Return
+
+	// health
+	DeviceGetEccMode                   func(device Device, current *EnableState, pending *EnableState) Return
+	DeviceGetTotalEccErrors            func(device Device, errorType MemoryErrorType, counterType EccCounterType, count *uint64) Return
+	DeviceGetRetiredPages              func(device Device, cause PageRetirementCause, count *uint32, addresses *uint64) Return
+	DeviceGetRetiredPagesPendingStatus func(device Device, pending *EnableState) Return
+	DeviceGetRemappedRows              func(device Device, corrRows *uint32, uncRows *uint32, isPending *uint32, failureOccurred *uint32) Return
+
+	// media engines
+	DeviceGetEncoderUtilization func(device Device, util *uint32, periodUs *uint32) Return
+	DeviceGetDecoderUtilization func(device Device, util *uint32, periodUs *uint32) Return
+	DeviceGetJpgUtilization     func(device Device, util *uint32, periodUs *uint32) Return
+	DeviceGetOfaUtilization     func(device Device, util *uint32, periodUs *uint32) Return
+	DeviceGetEncoderStats       func(device Device, sessions *uint32, fps *uint32, latencyUs *uint32) Return
+	DeviceGetEncoderSessions    func(device Device, count *uint32, infos *EncoderSessionInfo) Return
+	DeviceGetFBCStats           func(device Device, stats *FBCStats) Return
+
+	// events
+	EventSetCreate               func(set *EventSet) Return
+	EventSetFree                 func(set EventSet) Return
+	EventSetWait_v2              func(set EventSet, data *EventData, timeoutms uint32) Return
+	DeviceRegisterEvents         func(device Device, eventTypes uint64, set EventSet) Return
+	DeviceGetSupportedEventTypes func(device Device, eventTypes *uint64) Return
 
 	// oc: power limits
 	DeviceGetPowerManagementLimitConstraints func(device Device, min *uint32, max *uint32) Return
@@ -64,6 +100,17 @@ type RawSymbols struct {
 	DeviceGetSupportedGraphicsClocks func(device Device, memoryClockMHz uint32, count *uint32, clocksMHz *uint32) Return
 	DeviceSetGpuLockedClocks         func(device Device, min uint32, max uint32) Return
 	DeviceResetGpuLockedClocks       func(device Device) Return
+	DeviceSetMemoryLockedClocks      func(device Device, min uint32, max uint32) Return
+	DeviceResetMemoryLockedClocks    func(device Device) Return
+
+	// oc: temperature target
+	DeviceSetTemperatureThreshold func(device Device, threshold TemperatureThresholds, temp *int32) Return
+
+	// oc: application clocks
+	DeviceGetApplicationsClock        func(device Device, clockType ClockType, clockMHz *uint32) Return
+	DeviceGetDefaultApplicationsClock func(device Device, clockType ClockType, clockMHz *uint32) Return
+	DeviceSetApplicationsClocks       func(device Device, memClockMHz uint32, graphicsClockMHz uint32) Return
+	DeviceResetApplicationsClocks     func(device Device) Return
 }
 
 func NewRawSymbols() (*RawSymbols, error) {
@@ -86,18 +133,51 @@ func NewRawSymbols() (*RawSymbols, error) {
 	libloader.Bind(lib, &nvml.DeviceGetIndex, "nvmlDeviceGetIndex")
 	libloader.Bind(lib, &nvml.DeviceGetUUID, "nvmlDeviceGetUUID")
 	libloader.Bind(lib, &nvml.DeviceGetName, "nvmlDeviceGetName")
+	libloader.Bind(lib, &nvml.DeviceGetPciInfo_v3, "nvmlDeviceGetPciInfo_v3")
+	libloader.Bind(lib, &nvml.DeviceGetBoardPartNumber, "nvmlDeviceGetBoardPartNumber")
 
 	libloader.Bind(lib, &nvml.DeviceGetUtilizationRates, "nvmlDeviceGetUtilizationRates")
 	libloader.Bind(lib, &nvml.DeviceGetMemoryInfo, "nvmlDeviceGetMemoryInfo")
 	libloader.Bind(lib, &nvml.DeviceGetClockInfo, "nvmlDeviceGetClockInfo")
 	libloader.Bind(lib, &nvml.DeviceGetPowerUsage, "nvmlDeviceGetPowerUsage")
+	libloader.Bind(lib, &nvml.DeviceGetTotalEnergyConsumption, "nvmlDeviceGetTotalEnergyConsumption")
 	libloader.Bind(lib, &nvml.DeviceGetEnforcedPowerLimit, "nvmlDeviceGetEnforcedPowerLimit")
 	libloader.Bind(lib, &nvml.DeviceGetTemperature, "nvmlDeviceGetTemperature")
 	libloader.Bind(lib, &nvml.DeviceGetTemperatureV, "nvmlDeviceGetTemperatureV")
+	libloader.Bind(lib, &nvml.DeviceGetTemperatureThreshold, "nvmlDeviceGetTemperatureThreshold")
 	libloader.Bind(lib, &nvml.DeviceGetFanSpeed, "nvmlDeviceGetFanSpeed")
 	libloader.Bind(lib, &nvml.DeviceGetFanSpeedRPM, "nvmlDeviceGetFanSpeedRPM")
 	libloader.Bind(lib, &nvml.DeviceGetSamples, "nvmlDeviceGetSamples")
 	libloader.Bind(lib, &nvml.DeviceGetCurrentClocksEventReasons, "nvmlDeviceGetCurrentClocksEventReasons")
+	libloader.Bind(lib, &nvml.DeviceGetViolationStatus, "nvmlDeviceGetViolationStatus")
+	libloader.Bind(lib, &nvml.DeviceGetFieldValues, "nvmlDeviceGetFieldValues")
+	libloader.Bind(lib, &nvml.DeviceGetPerformanceState, "nvmlDeviceGetPerformanceState")
+	libloader.Bind(lib, &nvml.DeviceGetSupportedPerformanceStates, "nvmlDeviceGetSupportedPerformanceStates")
+	libloader.Bind(lib, &nvml.DeviceGetCurrPcieLinkGeneration, "nvmlDeviceGetCurrPcieLinkGeneration")
+	libloader.Bind(lib, &nvml.DeviceGetMaxPcieLinkGeneration, "nvmlDeviceGetMaxPcieLinkGeneration")
+	libloader.Bind(lib, &nvml.DeviceGetCurrPcieLinkWidth, "nvmlDeviceGetCurrPcieLinkWidth")
+	libloader.Bind(lib, &nvml.DeviceGetMaxPcieLinkWidth, "nvmlDeviceGetMaxPcieLinkWidth")
+	libloader.Bind(lib, &nvml.DeviceGetPcieThroughput, "nvmlDeviceGetPcieThroughput")
+	libloader.Bind(lib, &nvml.DeviceGetPcieReplayCounter, "nvmlDeviceGetPcieReplayCounter")
+
+	libloader.Bind(lib, &nvml.DeviceGetEccMode, "nvmlDeviceGetEccMode")
+	libloader.Bind(lib, &nvml.DeviceGetTotalEccErrors, "nvmlDeviceGetTotalEccErrors")
+	libloader.Bind(lib, &nvml.DeviceGetRetiredPages, "nvmlDeviceGetRetiredPages")
+	libloader.Bind(lib, &nvml.DeviceGetRetiredPagesPendingStatus, "nvmlDeviceGetRetiredPagesPendingStatus")
+	libloader.Bind(lib, &nvml.DeviceGetRemappedRows, "nvmlDeviceGetRemappedRows")
+	libloader.Bind(lib, &nvml.DeviceGetEncoderUtilization, "nvmlDeviceGetEncoderUtilization")
+	libloader.Bind(lib, &nvml.DeviceGetDecoderUtilization, "nvmlDeviceGetDecoderUtilization")
+	libloader.Bind(lib, &nvml.DeviceGetJpgUtilization, "nvmlDeviceGetJpgUtilization")
+	libloader.Bind(lib, &nvml.DeviceGetOfaUtilization, "nvmlDeviceGetOfaUtilization")
+	libloader.Bind(lib, &nvml.DeviceGetEncoderStats, "nvmlDeviceGetEncoderStats")
+	libloader.Bind(lib, &nvml.DeviceGetEncoderSessions, "nvmlDeviceGetEncoderSessions")
+	libloader.Bind(lib, &nvml.DeviceGetFBCStats, "nvmlDeviceGetFBCStats")
+
+	libloader.Bind(lib, &nvml.EventSetCreate, "nvmlEventSetCreate")
+	libloader.Bind(lib, &nvml.EventSetFree, "nvmlEventSetFree")
+	libloader.Bind(lib, &nvml.EventSetWait_v2, "nvmlEventSetWait_v2")
+	libloader.Bind(lib, &nvml.DeviceRegisterEvents, "nvmlDeviceRegisterEvents")
+	libloader.Bind(lib, &nvml.DeviceGetSupportedEventTypes, "nvmlDeviceGetSupportedEventTypes")
 
 	libloader.Bind(lib, &nvml.DeviceGetPowerManagementLimitConstraints, "nvmlDeviceGetPowerManagementLimitConstraints")
 	libloader.Bind(lib, &nvml.DeviceGetPowerManagementDefaultLimit, "nvmlDeviceGetPowerManagementDefaultLimit")
@@ -118,6 +198,15 @@ func NewRawSymbols() (*RawSymbols, error) {
 	libloader.Bind(lib, &nvml.DeviceGetSupportedGraphicsClocks, "nvmlDeviceGetSupportedGraphicsClocks")
 	libloader.Bind(lib, &nvml.DeviceSetGpuLockedClocks, "nvmlDeviceSetGpuLockedClocks")
 	libloader.Bind(lib, &nvml.DeviceResetGpuLockedClocks, "nvmlDeviceResetGpuLockedClocks")
+	libloader.Bind(lib, &nvml.DeviceSetMemoryLockedClocks, "nvmlDeviceSetMemoryLockedClocks")
+	libloader.Bind(lib, &nvml.DeviceResetMemoryLockedClocks, "nvmlDeviceResetMemoryLockedClocks")
+
+	libloader.Bind(lib, &nvml.DeviceSetTemperatureThreshold, "nvmlDeviceSetTemperatureThreshold")
+
+	libloader.Bind(lib, &nvml.DeviceGetApplicationsClock, "nvmlDeviceGetApplicationsClock")
+	libloader.Bind(lib, &nvml.DeviceGetDefaultApplicationsClock, "nvmlDeviceGetDefaultApplicationsClock")
+	libloader.Bind(lib, &nvml.DeviceSetApplicationsClocks, "nvmlDeviceSetApplicationsClocks")
+	libloader.Bind(lib, &nvml.DeviceResetApplicationsClocks, "nvmlDeviceResetApplicationsClocks")
 
 	return nvml, nil
 }
@@ -126,20 +215,8 @@ func (s *RawSymbols) StringFromReturn(r Return) string {
 	if s.ErrorString == nil {
 		return fmt.Sprintf("NVML Error %d", r)
 	}
-	ptr := s.ErrorString(r)
-	if ptr == 0 {
-		return "Unknown NVML Error"
+	if str := s.ErrorString(r); str != "" {
+		return str
 	}
-
-	p := (*byte)(unsafe.Pointer(ptr))
-
-	const maxLen = 1024
-
-	str := unsafe.Slice(p, maxLen)
-	n := bytes.IndexByte(str, 0)
-	if n == -1 {
-		n = maxLen
-	}
-
-	return string(str[:n])
+	return "Unknown NVML Error"
 }
@@ -0,0 +1,183 @@
+package nvidia
+
+import (
+	"fmt"
+
+	"nvtuner-go/internal/gpu"
+)
+
+var _ gpu.ParamSource = (*NvidiaGpu)(nil)
+
+// nv is the NVML device a param acts on. The sampler passes its current
+// handle, which may have been re-created since Params was called.
+func nv(dev gpu.Device) *NvidiaGpu { return dev.(*NvidiaGpu) }
+
+// Params declares the tuning knobs: power limit, clock offsets and locks,
+// application clocks, the temperature target and the offsets of every
+// P-state but P0. Their values are read with the telemetry, see
+// GetTelemetry, the P-state offsets with the info.
+func (g *NvidiaGpu) Params() []gpu.ParamDef {
+	mhz := gpu.UnitMHz.ReadPair
+	plMin, plMax := gpu.UnitMilliWatt.ReadPair(g.GetPlLim())
+	coGpuMin, coGpuMax := mhz(g.GetCoLimGpu())
+	coMemMin, coMemMax := mhz(g.GetCoLimMem())
+	clGpuMin, clGpuMax := mhz(g.GetClLimGpu())
+	clMemMin, clMemMax := mhz(g.GetClLimMem())
+	ttMin, ttMax := gpu.UnitCelsius.ReadPair(g.GetTempTargetLim())
+	appGpuDef, appMemDef := mhz(g.GetAppClockDefault())
+	clocks, _ := g.GetSupportedClocks()
+	gclks := clocks.GpuClocks()
+	appGpuMin, appGpuMax := tableRange(gclks)
+	appMemMin, appMemMax := tableRange(clocks.Mem)
+
+	ps := []gpu.ParamDef{
+		{
+			ID: "pl", Label: "POWER LIMIT", ShortLabel: "PL", Unit: gpu.UnitMilliWatt, Kind: gpu.KindValue,
+			Min: plMin, Max: plMax, Default: gpu.UnitMilliWatt.Read(g.GetPlDefault()), Step: 1000,
+			Get: func(dev gpu.Device) (int, error) { return nv(dev).GetPl() },
+			Set: func(dev gpu.Device, v int) error { return nv(dev).SetPl(v) },
+		},
+		{
+			ID: "gpu_co", Label: "GPU CO", ShortLabel: "G.CO", Unit: gpu.UnitMHz, Kind: gpu.KindOffset,
+			Min: coGpuMin, Max: coGpuMax, Default: gpu.UnitMHz.Of(0), Step: 15,
+			DependsOn: []string{"pl"},
+			Get:       func(dev gpu.Device) (int, error) { return nv(dev).GetCoGpu() },
+			Set:       func(dev gpu.Device, v int) error { return nv(dev).SetCoGpu(v) },
+		},
+		{
+			ID: "mem_co", Label: "MEMORY CO", ShortLabel: "M.CO", Unit: gpu.UnitMHz, Kind: gpu.KindOffset,
+			Min: coMemMin, Max: coMemMax, Default: gpu.UnitMHz.Of(0), Step: 50,
+			DependsOn: []string{"pl"},
+			Get:       func(dev gpu.Device) (int, error) { return nv(dev).GetCoMem() },
+			Set:       func(dev gpu.Device, v int) error { return nv(dev).SetCoMem(v) },
+		},
+		// clock locks default to open, which reads back as the ends of the table
+		{
+			ID: "gpu_cl", Label: "GPU LIMIT", ShortLabel: "G.CL", Unit: gpu.UnitMHz, Kind: gpu.KindLockMax,
+			Min: clGpuMin, Max: clGpuMax, Default: clGpuMax, Step: 15, Clocks: gclks,
+			// the lock caps the offset-shifted curve, so set it last
+			DependsOn: []string{"gpu_co"},
+			Get:       func(dev gpu.Device) (int, error) { return nv(dev).GetClGpu() },
+			Set:       func(dev gpu.Device, v int) error { return nv(dev).SetClGpu(v) },
+			Reset:     func(dev gpu.Device) error { return nv(dev).ResetClGpu() },
+		},
+		{
+			ID: "gpu_cl_min", Label: "GPU MIN", ShortLabel: "G.MN", Unit: gpu.UnitMHz, Kind: gpu.KindLockMin,
+			Min: clGpuMin, Max: clGpuMax, Default: clGpuMin, Step: 15, Clocks: gclks,
+			DependsOn: []string{"gpu_cl"},
+			Get:       func(dev gpu.Device) (int, error) { return nv(dev).GetClGpuMin() },
+			Set:       func(dev gpu.Device, v int) error { return nv(dev).SetClGpuMin(v) },
+			Reset:     func(dev gpu.Device) error { return nv(dev).ResetClGpu() },
+		},
+		{
+			ID: "mem_cl", Label: "MEM LIMIT", ShortLabel: "M.CL", Unit: gpu.UnitMHz, Kind: gpu.KindLockMax,
+			Min: clMemMin, Max: clMemMax, Default: clMemMax, Step: 50, Clocks: clocks.Mem,
+			DependsOn: []string{"mem_co"},
+			Get:       func(dev gpu.Device) (int, error) { return nv(dev).GetClMem() },
+			Set:       func(dev gpu.Device, v int) error { return nv(dev).SetClMem(v) },
+			Reset:     func(dev gpu.Device) error { return nv(dev).ResetClMem() },
+		},
+		{
+			ID: "mem_cl_min", Label: "MEM MIN", ShortLabel: "M.MN", Unit: gpu.UnitMHz, Kind: gpu.KindLockMin,
+			Min: clMemMin, Max: clMemMax, Default: clMemMin, Step: 50, Clocks: clocks.Mem,
+			DependsOn: []string{"mem_cl"},
+			Get:       func(dev gpu.Device) (int, error) { return nv(dev).GetClMemMin() },
+			Set:       func(dev gpu.Device, v int) error { return nv(dev).SetClMemMin(v) },
+			Reset:     func(dev gpu.Device) error { return nv(dev).ResetClMem() },
+		},
+		{
+			ID: "app_mem", Label: "APP MEM", ShortLabel: "A.MM", Unit: gpu.UnitMHz, Kind: gpu.KindAppClock,
+			Min: appMemMin, Max: appMemMax, Default: appMemDef, Step: 50,
+			Get:   func(dev gpu.Device) (int, error) { return nv(dev).GetAppClockMem() },
+			Set:   func(dev gpu.Device, v int) error { return nv(dev).SetAppClockMem(v) },
+			Reset: func(dev gpu.Device) error { return nv(dev).ResetAppClocks() },
+			Snap: func(_ gpu.DState, v int, _ func(string) gpu.Value) gpu.Value {
+				if v <= 0 && !appMemDef.Valid {
+					return appMemDef
+				}
+				_, mclk := snapAppClocks(0, v, clocks, appGpuDef, appMemDef)
+				return gpu.UnitMHz.Of(mclk)
+			},
+		},
+		{
+			ID: "app_gpu", Label: "APP GPU", ShortLabel: "A.GP", Unit: gpu.UnitMHz, Kind: gpu.KindAppClock,
+			Min: appGpuMin, Max: appGpuMax, Default: appGpuDef, Step: 15,
+			DependsOn: []string{"app_mem"},
+			Get:       func(dev gpu.Device) (int, error) { return nv(dev).GetAppClockGpu() },
+			Set:       func(dev gpu.Device, v int) error { return nv(dev).SetAppClockGpu(v) },
+			Reset:     func(dev gpu.Device) error { return nv(dev).ResetAppClocks() },
+			// the driver snaps to the graphics clocks of the app_mem set
+			// alongside, or of the current one
+			Snap: func(d gpu.DState, v int, with func(string) gpu.Value) gpu.Value {
+				if v <= 0 && !appGpuDef.Valid {
+					return appGpuDef
+				}
+				mclk := with("app_mem").Or(d.Setting("app_mem").Or(0))
+				gclk, _ := snapAppClocks(v, mclk, clocks, appGpuDef, appMemDef)
+				return gpu.UnitMHz.Of(gclk)
+			},
+		},
+		{
+			ID: "temp_target", Label: "TEMP TARGET", ShortLabel: "T.TG", Unit: gpu.UnitCelsius, Kind: gpu.KindTarget,
+			Min: ttMin, Max: ttMax, Step: 1,
+			Default: gpu.UnitCelsius.Of(0), // NVML has no default to read
+			Get:     func(dev gpu.Device) (int, error) { return nv(dev).GetTempTarget() },
+			Set:     func(dev gpu.Device, v int) error { return nv(dev).SetTempTarget(v) },
+			// <= 0 keeps whatever is set
+			Snap: func(d gpu.DState, v int, _ func(string) gpu.Value) gpu.Value {
+				lo, lok := ttMin.Get()
+				hi, hok := ttMax.Get()
+				switch {
+				case v <= 0:
+					return d.Setting("temp_target")
+				case !lok || !hok:
+					return gpu.UnitCelsius.Of(v)
+				}
+				return gpu.UnitCelsius.Of(min(max(v, lo), hi))
+			},
+		},
+	}
+
+	pstates, _ := g.GetPStates()
+	for _, p := range pstates {
+		if p != 0 {
+			ps = append(ps, g.pstateParams(p)...)
+		}
+	}
+	return ps
+}
+
+// pstateParams declares the clock offsets of a P-state other than P0.
+func (g *NvidiaGpu) pstateParams(ps int) []gpu.ParamDef {
+	coGpuMin, coGpuMax := gpu.UnitMHz.ReadPair(g.GetCoLimGpuAt(ps))
+	coMemMin, coMemMax := gpu.UnitMHz.ReadPair(g.GetCoLimMemAt(ps))
+	return []gpu.ParamDef{
+		{
+			ID: gpu.PStateParamID("gpu_co", ps), Label: fmt.Sprintf("P%d GPU CO", ps), ShortLabel: fmt.Sprintf("G.P%d", ps),
+			Unit: gpu.UnitMHz, Kind: gpu.KindOffset, PState: ps,
+			Min: coGpuMin, Max: coGpuMax, Default: gpu.UnitMHz.Of(0), Step: 15,
+			DependsOn: []string{"pl"},
+			Static:    true,
+			Get:       func(dev gpu.Device) (int, error) { return nv(dev).GetCoGpuAt(ps) },
+			Set:       func(dev gpu.Device, v int) error { return nv(dev).SetCoGpuAt(ps, v) },
+		},
+		{
+			ID: gpu.PStateParamID("mem_co", ps), Label: fmt.Sprintf("P%d MEM CO", ps), ShortLabel: fmt.Sprintf("M.P%d", ps),
+			Unit: gpu.UnitMHz, Kind: gpu.KindOffset, PState: ps,
+			Min: coMemMin, Max: coMemMax, Default: gpu.UnitMHz.Of(0), Step: 50,
+			DependsOn: []string{"pl"},
+			Static:    true,
+			Get:       func(dev gpu.Device) (int, error) { return nv(dev).GetCoMemAt(ps) },
+			Set:       func(dev gpu.Device, v int) error { return nv(dev).SetCoMemAt(ps, v) },
+		},
+	}
+}
+
+// tableRange returns the ends of an ascending clock table, invalid if it is
+// empty.
+func tableRange(t []int) (gpu.Value, gpu.Value) {
+	if len(t) == 0 {
+		return gpu.UnitMHz.NA(nil), gpu.UnitMHz.NA(nil)
+	}
+	return gpu.UnitMHz.Of(t[0]), gpu.UnitMHz.Of(t[len(t)-1])
+}
@@ -0,0 +1,83 @@
+package nvidia
+
+import (
+	"time"
+
+	"nvtuner-go/internal/gpu"
+)
+
+// pcieKeep is how long a nvmlDeviceGetPcieThroughput reading is reused.
+const pcieKeep = 5 * time.Second
+
+// pcieReading is a cached GetPcieThroughput result.
+type pcieReading struct {
+	tx, rx int
+	err    error
+	at     time.Time
+}
+
+func (g *NvidiaGpu) GetPcieLink() (gpu.PcieLink, error) {
+	return g.getPcieLink(g.symbols.DeviceGetCurrPcieLinkGeneration, g.symbols.DeviceGetCurrPcieLinkWidth, "Curr")
+}
+
+func (g *NvidiaGpu) GetPcieLinkMax() (gpu.PcieLink, error) {
+	return g.getPcieLink(g.symbols.DeviceGetMaxPcieLinkGeneration, g.symbols.DeviceGetMaxPcieLinkWidth, "Max")
+}
+
+func (g *NvidiaGpu) getPcieLink(genFn, widthFn func(Device, *uint32) Return, which string) (gpu.PcieLink, error) {
+	na := func(err error) (gpu.PcieLink, error) {
+		return gpu.PcieLink{Gen: gpu.UnitNone.NA(err), Width: gpu.UnitNone.NA(err)}, err
+	}
+	if genFn == nil {
+		return na(errMissing("nvmlDeviceGet" + which + "PcieLinkGeneration"))
+	}
+	if widthFn == nil {
+		return na(errMissing("nvmlDeviceGet" + which + "PcieLinkWidth"))
+	}
+	var gen, width uint32
+	if ret := genFn(g.handle, &gen); ret != SUCCESS {
+		return na(g.symbols.Error(ret))
+	}
+	if ret := widthFn(g.handle, &width); ret != SUCCESS {
+		return na(g.symbols.Error(ret))
+	}
+	return gpu.PcieLink{Gen: gpu.UnitNone.Of(int(gen)), Width: gpu.UnitNone.Of(int(width))}, nil
+}
+
+// GetPcieThroughput counts bytes over 20ms per direction, so a call takes
+// about 40ms. The reading is kept for pcieKeep; telemetry derives the rates
+// from the byte counter fields instead where the device has them.
+func (g *NvidiaGpu) GetPcieThroughput() (int, int, error) {
+	g.pcieMu.Lock()
+	defer g.pcieMu.Unlock()
+	if time.Since(g.pcie.at) >= pcieKeep {
+		tx, rx, err := g.readPcieThroughput()
+		g.pcie = pcieReading{tx, rx, err, time.Now()}
+	}
+	return g.pcie.tx, g.pcie.rx, g.pcie.err
+}
+
+func (g *NvidiaGpu) readPcieThroughput() (int, int, error) {
+	if g.symbols.DeviceGetPcieThroughput == nil {
+		return gpu.NO_VALUE, gpu.NO_VALUE, errMissing("nvmlDeviceGetPcieThroughput")
+	}
+	var tx, rx uint32
+	if ret := g.symbols.DeviceGetPcieThroughput(g.handle, PCIE_UTIL_TX_BYTES, &tx); ret != SUCCESS {
+		return gpu.NO_VALUE, gpu.NO_VALUE, g.symbols.Error(ret)
+	}
+	if ret := g.symbols.DeviceGetPcieThroughput(g.handle, PCIE_UTIL_RX_BYTES, &rx); ret != SUCCESS {
+		return gpu.NO_VALUE, gpu.NO_VALUE, g.symbols.Error(ret)
+	}
+	return int(tx), int(rx), nil
+}
+
+func (g *NvidiaGpu) GetPcieReplays() (int, error) {
+	if g.symbols.DeviceGetPcieReplayCounter == nil {
+		return gpu.NO_VALUE, errMissing("nvmlDeviceGetPcieReplayCounter")
+	}
+	var n uint32
+	if ret := g.symbols.DeviceGetPcieReplayCounter(g.handle, &n); ret != SUCCESS {
+		return gpu.NO_VALUE, g.symbols.Error(ret)
+	}
+	return int(n), nil
+}
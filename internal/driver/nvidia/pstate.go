@@ -0,0 +1,181 @@
+package nvidia
+
+import (
+	"fmt"
+
+	"nvtuner-go/internal/gpu"
+)
+
+func (g *NvidiaGpu) GetPState() (int, error) {
+	if g.symbols.DeviceGetPerformanceState == nil {
+		return gpu.NO_VALUE, errMissing("nvmlDeviceGetPerformanceState")
+	}
+
+	var ps Pstates
+	if ret := g.symbols.DeviceGetPerformanceState(g.handle, &ps); ret != SUCCESS {
+		return gpu.NO_VALUE, g.symbols.Error(ret)
+	}
+	if ps == PSTATE_UNKNOWN {
+		return gpu.NO_VALUE, fmt.Errorf("%w: unknown pstate", gpu.ErrNotSupported)
+	}
+	return int(ps), nil
+}
+
+func (g *NvidiaGpu) GetPStates() ([]int, error) {
+	if g.symbols.DeviceGetSupportedPerformanceStates == nil {
+		return nil, errMissing("nvmlDeviceGetSupportedPerformanceStates")
+	}
+
+	var buf [MAX_GPU_PERF_PSTATES]Pstates
+	if ret := g.symbols.DeviceGetSupportedPerformanceStates(g.handle, &buf[0], MAX_GPU_PERF_PSTATES); ret != SUCCESS {
+		return nil, g.symbols.Error(ret)
+	}
+
+	var res []int
+	for _, ps := range buf {
+		if ps != PSTATE_UNKNOWN {
+			res = append(res, int(ps))
+		}
+	}
+	return res, nil
+}
+
+func (g *NvidiaGpu) GetCoGpuAt(pstate int) (int, error) {
+	// fallback
+	if g.symbols.DeviceGetClockOffsets == nil {
+		if err := legacyPState(pstate); err != nil {
+			return gpu.NO_VALUE, err
+		}
+		var co int32
+		if ret := g.symbols.DeviceGetGpcClkVfOffset(g.handle, &co); ret != SUCCESS {
+			return gpu.NO_VALUE, g.symbols.Error(ret)
+		}
+		return int(co), nil
+	}
+
+	co, err := g.getClockOffset(CLOCK_GRAPHICS, pstate)
+	if err != nil {
+		return gpu.NO_VALUE, err
+	}
+	return int(co.ClockOffsetMHz), nil
+}
+
+func (g *NvidiaGpu) GetCoMemAt(pstate int) (int, error) {
+	// fallback
+	if g.symbols.DeviceGetClockOffsets == nil {
+		if err := legacyPState(pstate); err != nil {
+			return gpu.NO_VALUE, err
+		}
+		var co int32
+		if ret := g.symbols.DeviceGetMemClkVfOffset(g.handle, &co); ret != SUCCESS {
+			return gpu.NO_VALUE, g.symbols.Error(ret)
+		}
+		return int(co), nil
+	}
+
+	co, err := g.getClockOffset(CLOCK_MEM, pstate)
+	if err != nil {
+		return gpu.NO_VALUE, err
+	}
+	return int(co.ClockOffsetMHz), nil
+}
+
+func (g *NvidiaGpu) GetCoLimGpuAt(pstate int) (int, int, error) {
+	// fallback
+	if g.symbols.DeviceGetClockOffsets == nil {
+		if err := legacyPState(pstate); err != nil {
+			return gpu.NO_VALUE, gpu.NO_VALUE, err
+		}
+		var min, max int32
+		if ret := g.symbols.DeviceGetGpcClkMinMaxVfOffset(g.handle, &min, &max); ret != SUCCESS {
+			return gpu.NO_VALUE, gpu.NO_VALUE, g.symbols.Error(ret)
+		}
+		return int(min), int(max), nil
+	}
+
+	co, err := g.getClockOffset(CLOCK_GRAPHICS, pstate)
+	if err != nil {
+		return gpu.NO_VALUE, gpu.NO_VALUE, err
+	}
+	return int(co.MinClockOffsetMHz), int(co.MaxClockOffsetMHz), nil
+}
+
+func (g *NvidiaGpu) GetCoLimMemAt(pstate int) (int, int, error) {
+	// fallback
+	if g.symbols.DeviceGetClockOffsets == nil {
+		if err := legacyPState(pstate); err != nil {
+			return gpu.NO_VALUE, gpu.NO_VALUE, err
+		}
+		var min, max int32
+		if ret := g.symbols.DeviceGetMemClkMinMaxVfOffset(g.handle, &min, &max); ret != SUCCESS {
+			return gpu.NO_VALUE, gpu.NO_VALUE, g.symbols.Error(ret)
+		}
+		return int(min), int(max), nil
+	}
+
+	co, err := g.getClockOffset(CLOCK_MEM, pstate)
+	if err != nil {
+		return gpu.NO_VALUE, gpu.NO_VALUE, err
+	}
+	return int(co.MinClockOffsetMHz), int(co.MaxClockOffsetMHz), nil
+}
+
+func (g *NvidiaGpu) SetCoGpuAt(pstate, mhz int) error {
+	// fallback
+	if g.symbols.DeviceSetClockOffsets == nil {
+		if err := legacyPState(pstate); err != nil {
+			return err
+		}
+		if ret := g.symbols.DeviceSetGpcClkVfOffset(g.handle, int32(mhz)); ret != SUCCESS {
+			return g.symbols.Error(ret)
+		}
+		return nil
+	}
+	return g.setClockOffset(CLOCK_GRAPHICS, pstate, mhz)
+}
+
+func (g *NvidiaGpu) SetCoMemAt(pstate, mhz int) error {
+	// fallback
+	if g.symbols.DeviceSetClockOffsets == nil {
+		if err := legacyPState(pstate); err != nil {
+			return err
+		}
+		if ret := g.symbols.DeviceSetMemClkVfOffset(g.handle, int32(mhz)); ret != SUCCESS {
+			return g.symbols.Error(ret)
+		}
+		return nil
+	}
+	return g.setClockOffset(CLOCK_MEM, pstate, mhz)
+}
+
+func (g *NvidiaGpu) getClockOffset(typ ClockType, pstate int) (ClockOffset, error) {
+	var co ClockOffset
+	co.Version = VERSION_CLOCK_OFFSET
+	co.Type = typ
+	co.Pstate = Pstates(pstate)
+	if ret := g.symbols.DeviceGetClockOffsets(g.handle, &co); ret != SUCCESS {
+		return co, g.symbols.Error(ret)
+	}
+	return co, nil
+}
+
+func (g *NvidiaGpu) setClockOffset(typ ClockType, pstate, mhz int) error {
+	var co ClockOffset
+	co.Version = VERSION_CLOCK_OFFSET
+	co.Type = typ
+	co.Pstate = Pstates(pstate)
+	co.ClockOffsetMHz = int32(mhz)
+	if ret := g.symbols.DeviceSetClockOffsets(g.handle, &co); ret != SUCCESS {
+		return g.symbols.Error(ret)
+	}
+	return nil
+}
+
+// legacyPState rejects P-states the pre-r555 offset calls can't address;
+// they always act on P0.
+func legacyPState(pstate int) error {
+	if pstate != 0 {
+		return fmt.Errorf("%w: offsets for P%d need nvmlDeviceSetClockOffsets", gpu.ErrNotSupported, pstate)
+	}
+	return nil
+}
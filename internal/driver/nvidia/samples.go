@@ -0,0 +1,69 @@
+package nvidia
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"nvtuner-go/internal/gpu"
+)
+
+var samplingTypes = [gpu.SampleKinds]SamplingType{
+	gpu.SamplePower:    TOTAL_POWER_SAMPLES,
+	gpu.SampleUtilGpu:  GPU_UTILIZATION_SAMPLES,
+	gpu.SampleUtilMem:  MEMORY_UTILIZATION_SAMPLES,
+	gpu.SampleUtilEnc:  ENC_UTILIZATION_SAMPLES,
+	gpu.SampleUtilDec:  DEC_UTILIZATION_SAMPLES,
+	gpu.SampleClockGpu: PROCESSOR_CLK_SAMPLES,
+}
+
+func (g *NvidiaGpu) GetSamples(kind gpu.SampleKind, since time.Time) ([]gpu.Sample, error) {
+	if kind < 0 || kind >= gpu.SampleKinds {
+		return nil, fmt.Errorf("%w: unknown sample kind %d", gpu.ErrInvalidArgument, kind)
+	}
+	if g.symbols.DeviceGetSamples == nil {
+		return nil, errMissing("nvmlDeviceGetSamples")
+	}
+
+	var lastSeen uint64
+	if !since.IsZero() {
+		lastSeen = uint64(since.UnixMicro())
+	}
+
+	var (
+		valType ValueType
+		count   uint32
+	)
+	st := samplingTypes[kind]
+	ret := g.symbols.DeviceGetSamples(g.handle, st, lastSeen, &valType, &count, nil)
+	if ret == ERROR_NOT_FOUND {
+		return nil, nil // nothing new since lastSeen
+	}
+	if ret != SUCCESS {
+		return nil, g.symbols.Error(ret)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	raw := make([]Sample, count)
+	ret = g.symbols.DeviceGetSamples(g.handle, st, lastSeen, &valType, &count, &raw[0])
+	if ret == ERROR_NOT_FOUND {
+		return nil, nil
+	}
+	if ret != SUCCESS {
+		return nil, g.symbols.Error(ret)
+	}
+
+	res := make([]gpu.Sample, 0, count)
+	for _, r := range raw[:count] {
+		if r.TimeStamp <= lastSeen {
+			continue
+		}
+		v := r.SampleValue.AsFloat(valType)
+		res = append(res, gpu.Sample{Time: time.UnixMicro(int64(r.TimeStamp)), Value: v})
+	}
+	// the buffer is circular and not guaranteed to come back sorted
+	sort.Slice(res, func(i, j int) bool { return res[i].Time.Before(res[j].Time) })
+	return res, nil
+}
@@ -0,0 +1,223 @@
+package nvidia
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"nvtuner-go/internal/gpu"
+)
+
+// telemetryField is a value read in the one nvmlDeviceGetFieldValues call
+// per poll, with the getter that stands in when the batch can't deliver it.
+// Utilization, clocks, fan speed and the GPU temperature have no field ids.
+type telemetryField struct {
+	id       FieldId
+	unit     gpu.Unit
+	div      float64 // field value per unit
+	counter  bool    // the field counts up, the value is its rate per second
+	dst      func(t *gpu.Telemetry) *gpu.Value
+	fallback func(g *NvidiaGpu) gpu.Value
+}
+
+var telemetryFields = []telemetryField{
+	{FI_DEV_POWER_INSTANT, gpu.UnitMilliWatt, 1, false,
+		func(t *gpu.Telemetry) *gpu.Value { return &t.Power },
+		func(g *NvidiaGpu) gpu.Value { return gpu.UnitMilliWatt.Read(g.GetPower()) }},
+	{FI_DEV_TOTAL_ENERGY_CONSUMPTION, gpu.UnitMilliJoule, 1, false,
+		func(t *gpu.Telemetry) *gpu.Value { return &t.Energy },
+		func(g *NvidiaGpu) gpu.Value { return gpu.UnitMilliJoule.Read(g.getEnergy()) }},
+	{FI_DEV_MEMORY_TEMP, gpu.UnitCelsius, 1, false,
+		func(t *gpu.Telemetry) *gpu.Value { return &t.TempMem },
+		func(g *NvidiaGpu) gpu.Value {
+			return gpu.UnitCelsius.NA(fmt.Errorf("%w: memory temperature is only a field value", gpu.ErrNotSupported))
+		}},
+	{FI_DEV_PCIE_REPLAY_COUNTER, gpu.UnitNone, 1, false,
+		func(t *gpu.Telemetry) *gpu.Value { return &t.PcieReplays },
+		func(g *NvidiaGpu) gpu.Value { return gpu.UnitNone.Read(g.GetPcieReplays()) }},
+	{FI_DEV_PERF_POLICY_POWER, gpu.UnitMilliSecond, 1e6, false,
+		func(t *gpu.Telemetry) *gpu.Value { return &t.ThrottlePower },
+		func(g *NvidiaGpu) gpu.Value { return gpu.UnitMilliSecond.Read(g.getViolation(PERF_POLICY_POWER)) }},
+	{FI_DEV_PERF_POLICY_THERMAL, gpu.UnitMilliSecond, 1e6, false,
+		func(t *gpu.Telemetry) *gpu.Value { return &t.ThrottleTemp },
+		func(g *NvidiaGpu) gpu.Value { return gpu.UnitMilliSecond.Read(g.getViolation(PERF_POLICY_THERMAL)) }},
+	// the fallback samples for 40ms, so its reading is reused for a while
+	{FI_DEV_PCIE_COUNT_TX_BYTES, gpu.UnitKBps, 1024, true,
+		func(t *gpu.Telemetry) *gpu.Value { return &t.PcieTx },
+		func(g *NvidiaGpu) gpu.Value { tx, _, err := g.GetPcieThroughput(); return gpu.UnitKBps.Read(tx, err) }},
+	{FI_DEV_PCIE_COUNT_RX_BYTES, gpu.UnitKBps, 1024, true,
+		func(t *gpu.Telemetry) *gpu.Value { return &t.PcieRx },
+		func(g *NvidiaGpu) gpu.Value { _, rx, err := g.GetPcieThroughput(); return gpu.UnitKBps.Read(rx, err) }},
+}
+
+func (g *NvidiaGpu) GetTelemetry() (gpu.Telemetry, error) {
+	var t gpu.Telemetry
+
+	// no field ids for these; the first call also tells if the gpu is gone
+	ug, um, err := g.GetUtil()
+	if gpu.IsLost(err) {
+		return t, err
+	}
+	t.UtilGpu, t.UtilMem = gpu.UnitPercent.ReadPair(ug, um, err)
+	g.readTelemetryFields(&t)
+
+	t.Temp = gpu.UnitCelsius.Read(g.GetTemperature())
+	pct, rpm, err := g.GetFanSpeed()
+	t.FanPct, t.FanRPM = gpu.UnitPercent.Read(pct, err), gpu.UnitRPM.Read(rpm, err)
+	gclk, mclk, err := g.GetClocks()
+	t.ClockGpu, t.ClockMem = gpu.UnitMHz.ReadPair(gclk, mclk, err)
+	total, _, used, err := g.GetMemory()
+	t.MemTotal, t.MemUsed = gpu.UnitByte.ReadPair(total, used, err)
+	t.PState = gpu.UnitNone.Read(g.GetPState())
+	t.PcieLink, _ = g.GetPcieLink()
+	t.Media, _ = g.GetMediaStats()
+
+	// the params but the P-state offsets, see Params; the locks are checked
+	// against the clocks just read, which saves two queries
+	gl, ml := g.verifyLocks(gclk, mclk)
+	clGpuMin, clGpu := gpu.UnitMHz.ReadPair(g.lockBounds(gl, CLOCK_GRAPHICS))
+	clMemMin, clMem := gpu.UnitMHz.ReadPair(g.lockBounds(ml, CLOCK_MEM))
+	t.Settings = map[string]gpu.Value{
+		"pl":          gpu.UnitMilliWatt.Read(g.GetPl()),
+		"gpu_co":      gpu.UnitMHz.Read(g.GetCoGpu()),
+		"mem_co":      gpu.UnitMHz.Read(g.GetCoMem()),
+		"gpu_cl":      clGpu,
+		"gpu_cl_min":  clGpuMin,
+		"mem_cl":      clMem,
+		"mem_cl_min":  clMemMin,
+		"app_gpu":     gpu.UnitMHz.Read(g.GetAppClockGpu()),
+		"app_mem":     gpu.UnitMHz.Read(g.GetAppClockMem()),
+		"temp_target": gpu.UnitCelsius.Read(g.GetTempTarget()),
+	}
+	return t, nil
+}
+
+// readTelemetryFields fills the batched fields, each through its fallback
+// if the batch failed or the device rejected it. Fields the device rejects
+// are dropped from later queries.
+func (g *NvidiaGpu) readTelemetryFields(t *gpu.Telemetry) {
+	g.fieldsMu.Lock()
+	if g.fields == nil {
+		for _, f := range telemetryFields {
+			g.fields = append(g.fields, f.id)
+		}
+	}
+	ids := g.fields
+	g.fieldsMu.Unlock()
+
+	got := make(map[FieldId]bool, len(telemetryFields))
+	if vals, err := g.getFieldValues(ids); err == nil {
+		keep := make([]FieldId, 0, len(vals))
+		for _, v := range vals {
+			if v.NvmlReturn != SUCCESS {
+				if v.NvmlReturn != ERROR_NOT_SUPPORTED {
+					keep = append(keep, v.FieldId) // transient, try again next time
+				}
+				continue
+			}
+			keep = append(keep, v.FieldId)
+			i := slices.IndexFunc(telemetryFields, func(f telemetryField) bool { return f.id == v.FieldId })
+			if i < 0 {
+				continue
+			}
+			f := telemetryFields[i]
+			x := v.Value.AsFloat(v.ValueType)
+			got[f.id] = true
+			if f.counter {
+				var ok bool
+				if x, ok = g.rate(f.id, x); !ok {
+					*f.dst(t) = f.unit.NA(nil) // known on the next poll
+					continue
+				}
+			}
+			*f.dst(t) = f.unit.Of(int(x / f.div))
+		}
+		g.fieldsMu.Lock()
+		g.fields = keep
+		g.fieldsMu.Unlock()
+	}
+
+	for _, f := range telemetryFields {
+		if !got[f.id] {
+			*f.dst(t) = f.fallback(g)
+		}
+	}
+}
+
+// counterRead is a counter field as last read.
+type counterRead struct {
+	v  float64
+	at time.Time
+}
+
+// rate turns a read of a counter field into its rate per second since the
+// previous one; there is none on the first read or if the counter reset.
+func (g *NvidiaGpu) rate(id FieldId, v float64) (float64, bool) {
+	now := time.Now()
+	g.fieldsMu.Lock()
+	defer g.fieldsMu.Unlock()
+	prev, ok := g.counters[id]
+	if g.counters == nil {
+		g.counters = make(map[FieldId]counterRead)
+	}
+	g.counters[id] = counterRead{v, now}
+	dt := now.Sub(prev.at).Seconds()
+	if !ok || dt <= 0 || v < prev.v {
+		return 0, false
+	}
+	return (v - prev.v) / dt, true
+}
+
+// getEnergy is the field's fallback; mJ since driver load.
+func (g *NvidiaGpu) getEnergy() (int, error) {
+	if g.symbols.DeviceGetTotalEnergyConsumption == nil {
+		return gpu.NO_VALUE, errMissing("nvmlDeviceGetTotalEnergyConsumption")
+	}
+	var mj uint64
+	if ret := g.symbols.DeviceGetTotalEnergyConsumption(g.handle, &mj); ret != SUCCESS {
+		return gpu.NO_VALUE, g.symbols.Error(ret)
+	}
+	return int(mj), nil
+}
+
+// getViolation is the throttle fields' fallback; ms since driver load.
+func (g *NvidiaGpu) getViolation(policy PerfPolicyType) (int, error) {
+	if g.symbols.DeviceGetViolationStatus == nil {
+		return gpu.NO_VALUE, errMissing("nvmlDeviceGetViolationStatus")
+	}
+	var vt ViolationTime
+	if ret := g.symbols.DeviceGetViolationStatus(g.handle, policy, &vt); ret != SUCCESS {
+		return gpu.NO_VALUE, g.symbols.Error(ret)
+	}
+	return int(vt.ViolationTime / 1e6), nil
+}
+
+// probeField tells if the device answers a field.
+func (g *NvidiaGpu) probeField(id FieldId) error {
+	vals, err := g.getFieldValues([]FieldId{id})
+	if err != nil {
+		return err
+	}
+	if vals[0].NvmlReturn != SUCCESS {
+		return g.symbols.Error(vals[0].NvmlReturn)
+	}
+	return nil
+}
+
+func (g *NvidiaGpu) getFieldValues(ids []FieldId) ([]FieldValue, error) {
+	if g.symbols.DeviceGetFieldValues == nil {
+		return nil, errMissing("nvmlDeviceGetFieldValues")
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: no fields", gpu.ErrInvalidArgument)
+	}
+
+	vals := make([]FieldValue, len(ids))
+	for i, id := range ids {
+		vals[i].FieldId = id
+	}
+	if ret := g.symbols.DeviceGetFieldValues(g.handle, int32(len(vals)), &vals[0]); ret != SUCCESS {
+		return nil, g.symbols.Error(ret)
+	}
+	return vals, nil
+}
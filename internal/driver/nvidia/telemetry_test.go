@@ -0,0 +1,91 @@
+package nvidia
+
+import (
+	"testing"
+	"unsafe"
+
+	"nvtuner-go/internal/gpu"
+)
+
+// stubSymbols answers the telemetry field batch and the getters standing in
+// for it, counting calls into the library. Fields in unsupported are
+// rejected by the batch; without batch the library lacks
+// nvmlDeviceGetFieldValues.
+func stubSymbols(calls *int, batch bool, unsupported ...FieldId) *RawSymbols {
+	ok := func() Return { *calls++; return SUCCESS }
+	s := &RawSymbols{
+		DeviceGetPowerUsage:             func(_ Device, mw *uint32) Return { *mw = 250000; return ok() },
+		DeviceGetTotalEnergyConsumption: func(_ Device, mj *uint64) Return { *mj = 1e9; return ok() },
+		DeviceGetViolationStatus:        func(_ Device, _ PerfPolicyType, vt *ViolationTime) Return { return ok() },
+		DeviceGetPcieThroughput:         func(_ Device, _ PcieUtilCounter, kbps *uint32) Return { *kbps = 1000; return ok() },
+		DeviceGetPcieReplayCounter:      func(_ Device, n *uint32) Return { return ok() },
+	}
+	if batch {
+		s.DeviceGetFieldValues = func(_ Device, n int32, first *FieldValue) Return {
+			vals := unsafe.Slice(first, n)
+			for i := range vals {
+				v := &vals[i]
+				v.NvmlReturn, v.ValueType = SUCCESS, VALUE_TYPE_UNSIGNED_LONG_LONG
+				for _, id := range unsupported {
+					if v.FieldId == id {
+						v.NvmlReturn = ERROR_NOT_SUPPORTED
+					}
+				}
+				*(*uint64)(unsafe.Pointer(&v.Value.Data)) = uint64(i+1) * 1000
+			}
+			return ok()
+		}
+	}
+	return s
+}
+
+func TestTelemetryFieldsBatched(t *testing.T) {
+	var calls int
+	g := &NvidiaGpu{symbols: stubSymbols(&calls, true)}
+	var tel gpu.Telemetry
+	g.readTelemetryFields(&tel)
+	if calls != 1 {
+		t.Errorf("read took %d calls, want the one batch", calls)
+	}
+	if !tel.Power.Valid || !tel.Energy.Valid || !tel.TempMem.Valid {
+		t.Errorf("batched fields left invalid: %+v", tel)
+	}
+	if tel.PcieTx.Valid {
+		t.Errorf("PCIe TX rate on the first read of its counter: %s", tel.PcieTx)
+	}
+}
+
+func TestTelemetryFieldsFallback(t *testing.T) {
+	var calls int
+	g := &NvidiaGpu{symbols: stubSymbols(&calls, true, FI_DEV_POWER_INSTANT, FI_DEV_MEMORY_TEMP)}
+	var tel gpu.Telemetry
+	g.readTelemetryFields(&tel)
+	if !tel.Power.Valid || tel.Power.V != 250000 {
+		t.Errorf("power = %s, want it from nvmlDeviceGetPowerUsage", tel.Power)
+	}
+	if tel.TempMem.Valid {
+		t.Errorf("memory temperature = %s, has no getter", tel.TempMem)
+	}
+	if want := len(telemetryFields) - 2; len(g.fields) != want {
+		t.Errorf("%d fields queried next time, want %d", len(g.fields), want)
+	}
+
+	calls = 0
+	g.readTelemetryFields(&tel)
+	if calls != 2 { // the batch and nvmlDeviceGetPowerUsage
+		t.Errorf("second read took %d calls, want 2", calls)
+	}
+}
+
+func benchmarkTelemetryFields(b *testing.B, batch bool) {
+	var calls int
+	g := &NvidiaGpu{symbols: stubSymbols(&calls, batch)}
+	var tel gpu.Telemetry
+	for b.Loop() {
+		g.readTelemetryFields(&tel)
+	}
+	b.ReportMetric(float64(calls)/float64(b.N), "calls/op")
+}
+
+func BenchmarkTelemetryFieldsBatched(b *testing.B)  { benchmarkTelemetryFields(b, true) }
+func BenchmarkTelemetryFieldsFallback(b *testing.B) { benchmarkTelemetryFields(b, false) }
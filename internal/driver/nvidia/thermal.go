@@ -0,0 +1,61 @@
+package nvidia
+
+import (
+	"nvtuner-go/internal/gpu"
+)
+
+// GetTempThresholds reads every threshold the device reports and leaves the
+// others invalid. It only fails if the library lacks the call.
+func (g *NvidiaGpu) GetTempThresholds() (gpu.TempThresholds, error) {
+	if g.symbols.DeviceGetTemperatureThreshold == nil {
+		err := errMissing("nvmlDeviceGetTemperatureThreshold")
+		na := gpu.UnitCelsius.NA(err)
+		return gpu.TempThresholds{Shutdown: na, Slowdown: na, MemMax: na, GpuMax: na}, err
+	}
+	return gpu.TempThresholds{
+		Shutdown: gpu.UnitCelsius.Read(g.getTempThreshold(TEMPERATURE_THRESHOLD_SHUTDOWN)),
+		Slowdown: gpu.UnitCelsius.Read(g.getTempThreshold(TEMPERATURE_THRESHOLD_SLOWDOWN)),
+		MemMax:   gpu.UnitCelsius.Read(g.getTempThreshold(TEMPERATURE_THRESHOLD_MEM_MAX)),
+		GpuMax:   gpu.UnitCelsius.Read(g.getTempThreshold(TEMPERATURE_THRESHOLD_GPU_MAX)),
+	}, nil
+}
+
+// GetTempTarget returns the acoustic threshold, the temperature the driver
+// throttles to keep the GPU at.
+func (g *NvidiaGpu) GetTempTarget() (int, error) {
+	return g.getTempThreshold(TEMPERATURE_THRESHOLD_ACOUSTIC_CURR)
+}
+
+func (g *NvidiaGpu) GetTempTargetLim() (int, int, error) {
+	lo, err := g.getTempThreshold(TEMPERATURE_THRESHOLD_ACOUSTIC_MIN)
+	if err != nil {
+		return gpu.NO_VALUE, gpu.NO_VALUE, err
+	}
+	hi, err := g.getTempThreshold(TEMPERATURE_THRESHOLD_ACOUSTIC_MAX)
+	if err != nil {
+		return gpu.NO_VALUE, gpu.NO_VALUE, err
+	}
+	return lo, hi, nil
+}
+
+func (g *NvidiaGpu) SetTempTarget(c int) error {
+	if g.symbols.DeviceSetTemperatureThreshold == nil {
+		return errMissing("nvmlDeviceSetTemperatureThreshold")
+	}
+	temp := int32(c)
+	if ret := g.symbols.DeviceSetTemperatureThreshold(g.handle, TEMPERATURE_THRESHOLD_ACOUSTIC_CURR, &temp); ret != SUCCESS {
+		return g.symbols.Error(ret)
+	}
+	return nil
+}
+
+func (g *NvidiaGpu) getTempThreshold(typ TemperatureThresholds) (int, error) {
+	if g.symbols.DeviceGetTemperatureThreshold == nil {
+		return gpu.NO_VALUE, errMissing("nvmlDeviceGetTemperatureThreshold")
+	}
+	var temp uint32
+	if ret := g.symbols.DeviceGetTemperatureThreshold(g.handle, typ, &temp); ret != SUCCESS {
+		return gpu.NO_VALUE, g.symbols.Error(ret)
+	}
+	return int(temp), nil
+}
@@ -19,14 +19,61 @@ type Temperature struct {
 	SensorType  TemperatureSensors
 	Temperature uint32
 }
+type FieldValue struct {
+	FieldId     FieldId
+	ScopeId     uint32
+	Timestamp   int64 // us since epoch
+	LatencyUsec int64
+	ValueType   ValueType
+	NvmlReturn  Return
+	Value       Value
+}
 type Utilization struct{ Gpu, Memory uint32 }
+type PciInfo struct {
+	BusIdLegacy    [16]byte
+	Domain         uint32
+	Bus            uint32
+	Device         uint32
+	PciDeviceId    uint32 // device id << 16 | vendor id
+	PciSubSystemId uint32
+	BusId          [DEVICE_PCI_BUS_ID_BUFFER_SIZE]byte
+}
 type Memory struct{ Total, Free, Used uint64 }
+type EncoderSessionInfo struct {
+	SessionId      uint32
+	Pid            uint32
+	VgpuInstance   uint32
+	CodecType      EncoderType
+	HResolution    uint32
+	VResolution    uint32
+	AverageFps     uint32
+	AverageLatency uint32 // us
+}
+type FBCStats struct{ SessionsCount, AverageFPS, AverageLatency uint32 }
+type ViolationTime struct{ ReferenceTime, ViolationTime uint64 } // ns
+type EventData struct {
+	Device            Device
+	EventType         uint64
+	EventData         uint64 // Xid code for XidCriticalError
+	GpuInstanceId     uint32
+	ComputeInstanceId uint32
+}
 
-type ClockType int32          // nvmlClockType_t
-type SamplingType int32       // nvmlSamplingType_t
-type TemperatureSensors int32 // nvmlTemperatureSensors_t
-type ValueType int32          // nvmlValueType_t
-type Pstates int32            // nvmlPstates_t
+type ClockType int32             // nvmlClockType_t
+type SamplingType int32          // nvmlSamplingType_t
+type TemperatureSensors int32    // nvmlTemperatureSensors_t
+type TemperatureThresholds int32 // nvmlTemperatureThresholds_t
+type ValueType int32             // nvmlValueType_t
+type Pstates int32               // nvmlPstates_t
+type FieldId uint32              // NVML_FI_*
+type PcieUtilCounter int32       // nvmlPcieUtilCounter_t
+type EnableState int32           // nvmlEnableState_t
+type MemoryErrorType int32       // nvmlMemoryErrorType_t
+type EccCounterType int32        // nvmlEccCounterType_t
+type EncoderType int32           // nvmlEncoderType_t
+type PageRetirementCause int32   // nvmlPageRetirementCause_t
+type PerfPolicyType int32        // nvmlPerfPolicyType_t
+type EventSet uintptr
 
 const (
 	CLOCK_GRAPHICS ClockType = 0
@@ -46,6 +93,47 @@ const (
 const (
 	TEMPERATURE_GPU TemperatureSensors = 0
 )
+const (
+	TEMPERATURE_THRESHOLD_SHUTDOWN      TemperatureThresholds = 0
+	TEMPERATURE_THRESHOLD_SLOWDOWN      TemperatureThresholds = 1
+	TEMPERATURE_THRESHOLD_MEM_MAX       TemperatureThresholds = 2
+	TEMPERATURE_THRESHOLD_GPU_MAX       TemperatureThresholds = 3
+	TEMPERATURE_THRESHOLD_ACOUSTIC_MIN  TemperatureThresholds = 4
+	TEMPERATURE_THRESHOLD_ACOUSTIC_CURR TemperatureThresholds = 5
+	TEMPERATURE_THRESHOLD_ACOUSTIC_MAX  TemperatureThresholds = 6
+)
+const (
+	PERF_POLICY_POWER   PerfPolicyType = 0
+	PERF_POLICY_THERMAL PerfPolicyType = 1
+)
+const (
+	FEATURE_DISABLED EnableState = 0
+	FEATURE_ENABLED  EnableState = 1
+)
+const (
+	MEMORY_ERROR_TYPE_CORRECTED   MemoryErrorType = 0
+	MEMORY_ERROR_TYPE_UNCORRECTED MemoryErrorType = 1
+)
+const (
+	VOLATILE_ECC  EccCounterType = 0
+	AGGREGATE_ECC EccCounterType = 1
+)
+const (
+	PAGE_RETIREMENT_CAUSE_MULTIPLE_SINGLE_BIT_ECC_ERRORS PageRetirementCause = 0
+	PAGE_RETIREMENT_CAUSE_DOUBLE_BIT_ECC_ERROR           PageRetirementCause = 1
+)
+const ( // nvmlEventType*
+	EVENT_TYPE_SINGLE_BIT_ECC_ERROR uint64 = 0x0001
+	EVENT_TYPE_DOUBLE_BIT_ECC_ERROR uint64 = 0x0002
+	EVENT_TYPE_PSTATE               uint64 = 0x0004
+	EVENT_TYPE_XID_CRITICAL_ERROR   uint64 = 0x0008
+	EVENT_TYPE_CLOCK                uint64 = 0x0010
+	EVENT_TYPE_POWER_SOURCE_CHANGE  uint64 = 0x0080
+)
+const (
+	PCIE_UTIL_TX_BYTES PcieUtilCounter = 0
+	PCIE_UTIL_RX_BYTES PcieUtilCounter = 1
+)
 const (
 	VALUE_TYPE_DOUBLE             ValueType = 0
 	VALUE_TYPE_UNSIGNED_INT       ValueType = 1
@@ -55,6 +143,18 @@ const (
 	VALUE_TYPE_SIGNED_INT         ValueType = 5
 	VALUE_TYPE_UNSIGNED_SHORT     ValueType = 6
 )
+const (
+	FI_DEV_PERF_POLICY_POWER            FieldId = 74  // ns throttled by the power limit
+	FI_DEV_PERF_POLICY_THERMAL          FieldId = 75  // ns throttled by temperature
+	FI_DEV_MEMORY_TEMP                  FieldId = 82  // C
+	FI_DEV_TOTAL_ENERGY_CONSUMPTION     FieldId = 83  // mJ since driver load
+	FI_DEV_PCIE_REPLAY_COUNTER          FieldId = 94  //
+	FI_DEV_PCIE_REPLAY_ROLLOVER_COUNTER FieldId = 95  //
+	FI_DEV_POWER_AVERAGE                FieldId = 185 // mW, 1s average
+	FI_DEV_POWER_INSTANT                FieldId = 186 // mW
+	FI_DEV_PCIE_COUNT_TX_BYTES          FieldId = 197 // since driver load
+	FI_DEV_PCIE_COUNT_RX_BYTES          FieldId = 198 // since driver load
+)
 const (
 	PSTATE_0 Pstates = iota
 	PSTATE_1
@@ -72,6 +172,9 @@ const (
 	PSTATE_13
 	PSTATE_14
 	PSTATE_15
+	PSTATE_UNKNOWN Pstates = 32
+
+	MAX_GPU_PERF_PSTATES = 16
 )
 
 const (
@@ -79,6 +182,8 @@ const (
 	SYSTEM_NVML_VERSION_BUFFER_SIZE   = 80
 	DEVICE_UUID_BUFFER_SIZE           = 80
 	DEVICE_NAME_BUFFER_SIZE           = 64
+	DEVICE_PCI_BUS_ID_BUFFER_SIZE     = 32
+	DEVICE_PART_NUMBER_BUFFER_SIZE    = 80
 )
 const (
 	SUCCESS                         Return = 0
@@ -114,3 +219,9 @@ const (
 	ERROR_RESET_TYPE_NOT_SUPPORTED  Return = 30
 	ERROR_UNKNOWN                   Return = 999
 )
+
+const (
+	ENCODER_QUERY_H264 EncoderType = 0
+	ENCODER_QUERY_HEVC EncoderType = 1
+	ENCODER_QUERY_AV1  EncoderType = 2
+)
@@ -10,3 +10,24 @@ func nvmlStructVersion(size uintptr, ver uint32) uint32 {
 func (v Value) AsUint() uint32 {
 	return *(*uint32)(unsafe.Pointer(&v.Data))
 }
+
+// AsFloat reads the union according to its nvmlValueType_t.
+func (v Value) AsFloat(t ValueType) float64 {
+	p := unsafe.Pointer(&v.Data)
+	switch t {
+	case VALUE_TYPE_DOUBLE:
+		return *(*float64)(p)
+	case VALUE_TYPE_UNSIGNED_INT:
+		return float64(*(*uint32)(p))
+	case VALUE_TYPE_UNSIGNED_LONG, VALUE_TYPE_UNSIGNED_LONG_LONG:
+		return float64(*(*uint64)(p))
+	case VALUE_TYPE_SIGNED_LONG_LONG:
+		return float64(*(*int64)(p))
+	case VALUE_TYPE_SIGNED_INT:
+		return float64(*(*int32)(p))
+	case VALUE_TYPE_UNSIGNED_SHORT:
+		return float64(*(*uint16)(p))
+	default:
+		return float64(v.AsUint())
+	}
+}
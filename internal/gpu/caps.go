@@ -0,0 +1,104 @@
+package gpu
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Access tells what can be done with a metric or tuning parameter.
+type Access int
+
+const (
+	AccessNone      Access = 0 // unsupported
+	AccessRead      Access = 1 << 0
+	AccessWrite     Access = 1 << 1 // settable, even if the value can't be read back
+	AccessReadWrite        = AccessRead | AccessWrite
+)
+
+func (a Access) Readable() bool { return a&AccessRead != 0 }
+func (a Access) Writable() bool { return a&AccessWrite != 0 }
+
+func (a Access) String() string {
+	switch a {
+	case AccessRead:
+		return "ro"
+	case AccessWrite:
+		return "wo"
+	case AccessReadWrite:
+		return "rw"
+	default:
+		return "none"
+	}
+}
+
+func (a Access) MarshalText() ([]byte, error) { return []byte(a.String()), nil }
+
+type CapKind string
+
+const (
+	CapMetric CapKind = "metric"
+	CapParam  CapKind = "param" // IDs match config and tuning parameter IDs
+)
+
+type Capability struct {
+	ID     string  `json:"id"`
+	Kind   CapKind `json:"kind"`
+	Access Access  `json:"access"`
+	Reason string  `json:"reason,omitempty"` // why not readable or writable
+}
+
+type Capabilities []Capability
+
+// PStateParamID names the per-P-state variant of a parameter, e.g. "gpu_co_p2".
+func PStateParamID(id string, pstate int) string {
+	return fmt.Sprintf("%s_p%d", id, pstate)
+}
+
+// Get returns the capability with the given ID, or an unsupported one.
+func (cs Capabilities) Get(id string) Capability {
+	for _, c := range cs {
+		if c.ID == id {
+			return c
+		}
+	}
+	return Capability{ID: id, Access: AccessNone, Reason: "unknown"}
+}
+
+// Has tells if the device reported on the given ID.
+func (cs Capabilities) Has(id string) bool {
+	for _, c := range cs {
+		if c.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Capability) Readable() bool { return c.Access.Readable() }
+func (c Capability) Writable() bool { return c.Access.Writable() }
+
+// ProbeRead turns the result of a getter into a read-only capability.
+func ProbeRead(id string, kind CapKind, err error) Capability {
+	c := Capability{ID: id, Kind: kind, Access: AccessRead}
+	if err != nil {
+		c.Access, c.Reason = AccessNone, err.Error()
+	}
+	return c
+}
+
+// ProbeParam builds a parameter capability from the result of its getter and
+// from whatever the driver found out about setting it.
+func ProbeParam(id string, readErr, writeErr error) Capability {
+	c := ProbeRead(id, CapParam, readErr)
+	var why []string
+	if readErr != nil {
+		why = append(why, "read: "+readErr.Error())
+	}
+	if writeErr == nil {
+		c.Access |= AccessWrite
+	} else {
+		why = append(why, "write: "+writeErr.Error())
+	}
+	c.Reason = strings.Join(why, "; ")
+	return c
+}
@@ -0,0 +1,43 @@
+package gpu
+
+import "slices"
+
+// ClockTable is the set of clocks a device can be locked to. Graphics
+// clocks depend on the memory clock, hence one list per memory clock.
+type ClockTable struct {
+	Mem []int         `json:"mem"` // MHz, ascending
+	Gpu map[int][]int `json:"gpu"` // MHz, ascending, by memory clock
+}
+
+// GpuClocks merges the graphics clocks of every memory clock.
+func (t ClockTable) GpuClocks() []int {
+	var res []int
+	for _, cs := range t.Gpu {
+		res = append(res, cs...)
+	}
+	slices.Sort(res)
+	return slices.Compact(res)
+}
+
+// SnapDown returns the highest clock in table not above mhz, or the lowest
+// one if there is none. table must be ascending.
+func SnapDown(table []int, mhz int) int {
+	if len(table) == 0 {
+		return mhz
+	}
+	i, found := slices.BinarySearch(table, mhz)
+	if found {
+		return table[i]
+	}
+	return table[max(0, i-1)]
+}
+
+// SnapUp returns the lowest clock in table not below mhz, or the highest one
+// if there is none. table must be ascending.
+func SnapUp(table []int, mhz int) int {
+	if len(table) == 0 {
+		return mhz
+	}
+	i, _ := slices.BinarySearch(table, mhz)
+	return table[min(i, len(table)-1)]
+}
@@ -0,0 +1,63 @@
+package gpu
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Drivers wrap these so callers can tell failures apart without knowing the
+// vendor library, e.g. errors.Is(err, ErrNoPermission).
+var (
+	ErrNotSupported      = errors.New("not supported")
+	ErrNoPermission      = errors.New("no permission")
+	ErrInvalidArgument   = errors.New("invalid argument")
+	ErrGpuLost           = errors.New("gpu is lost")
+	ErrDriverNotLoaded   = errors.New("driver not loaded")
+	ErrTimeout           = errors.New("timeout")
+	ErrInUse             = errors.New("in use")
+	ErrResetRequired     = errors.New("gpu reset required")
+	ErrInsufficientPower = errors.New("insufficient external power")
+)
+
+// ErrLockUnknown is returned by the clock lock getters when the lock was not
+// set through this handle, or was changed by someone else since.
+var ErrLockUnknown = fmt.Errorf("%w: clock lock set outside nvtuner", ErrNotSupported)
+
+// IsLost tells whether err means the device or the whole driver is gone,
+// as opposed to a single failed query.
+func IsLost(err error) bool {
+	return errors.Is(err, ErrGpuLost) || errors.Is(err, ErrDriverNotLoaded)
+}
+
+// IsRetryable tells whether trying the same call again later may succeed.
+func IsRetryable(err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, ErrNotSupported), errors.Is(err, ErrNoPermission),
+		errors.Is(err, ErrInvalidArgument), errors.Is(err, ErrResetRequired):
+		return false
+	default:
+		return true
+	}
+}
+
+// Hint suggests what the user can do about err, or returns "".
+func Hint(err error) string {
+	switch {
+	case errors.Is(err, ErrNoPermission):
+		return "run as root / Administrator"
+	case errors.Is(err, ErrLockUnknown):
+		return "apply or reset the clock lock to track it"
+	case errors.Is(err, ErrNotSupported):
+		return "not supported by this GPU or driver"
+	case errors.Is(err, ErrResetRequired):
+		return "reset the GPU or reboot"
+	case errors.Is(err, ErrGpuLost):
+		return "check the kernel log for Xid errors"
+	case errors.Is(err, ErrDriverNotLoaded):
+		return "is the GPU driver loaded?"
+	default:
+		return ""
+	}
+}
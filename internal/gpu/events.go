@@ -0,0 +1,164 @@
+package gpu
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type EventKind int
+
+const (
+	EventClock        EventKind = iota // clocks changed; NVML only sends it on Kepler
+	EventPowerSource                   // switched between AC and battery
+	EventXid                           // critical error, Data is the Xid code
+	EventEccSingleBit                  // corrected memory error
+	EventEccDoubleBit                  // uncorrected memory error
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventClock:
+		return "clock change"
+	case EventPowerSource:
+		return "power source change"
+	case EventXid:
+		return "Xid"
+	case EventEccSingleBit:
+		return "single-bit ECC error"
+	case EventEccDoubleBit:
+		return "double-bit ECC error"
+	default:
+		return fmt.Sprintf("event %d", int(k))
+	}
+}
+
+// Event is something a device reported on its own.
+type Event struct {
+	Kind  EventKind
+	Index int
+	UUID  string
+	Time  time.Time
+	Data  uint64 // kind specific
+}
+
+func (e Event) String() string {
+	if e.Kind == EventXid {
+		return fmt.Sprintf("GPU %d: Xid %d", e.Index, e.Data)
+	}
+	return fmt.Sprintf("GPU %d: %s", e.Index, e.Kind)
+}
+
+// EventWaiter is implemented by managers that can block for device events.
+type EventWaiter interface {
+	// WaitEvent returns the next event of any device returned by Devices,
+	// or ErrTimeout if there was none within timeout.
+	WaitEvent(timeout time.Duration) (Event, error)
+}
+
+const (
+	eventWait    = 500 * time.Millisecond // bounds how long Stop blocks
+	eventBackoff = time.Second            // after errors, e.g. while the driver re-inits
+)
+
+// Listener waits for device events from its own goroutine and hands them
+// to every subscriber. It keeps running across driver re-inits.
+type Listener struct {
+	src  EventWaiter
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// NewListener starts listening, or fails with ErrNotSupported if mgr can't
+// deliver events.
+func NewListener(mgr Manager) (*Listener, error) {
+	src, ok := mgr.(EventWaiter)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has no events", ErrNotSupported, mgr.GetManagerName())
+	}
+	l := &Listener{
+		src:  src,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+		subs: make(map[chan Event]struct{}),
+	}
+	go l.run()
+	return l, nil
+}
+
+// Subscribe returns a channel receiving every event from now on. Events are
+// dropped, not queued, while the buffer is full. cancel closes the channel.
+func (l *Listener) Subscribe(buf int) (events <-chan Event, cancel func()) {
+	ch := make(chan Event, buf)
+	l.mu.Lock()
+	l.subs[ch] = struct{}{}
+	l.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if _, ok := l.subs[ch]; ok {
+				delete(l.subs, ch)
+				close(ch)
+			}
+		})
+	}
+}
+
+// Stop ends listening and closes every subscription. Call it before shutting
+// the driver down.
+func (l *Listener) Stop() {
+	l.once.Do(func() {
+		close(l.stop)
+		<-l.done
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for ch := range l.subs {
+			close(ch)
+		}
+		clear(l.subs)
+	})
+}
+
+func (l *Listener) run() {
+	defer close(l.done)
+	for {
+		select {
+		case <-l.stop:
+			return
+		default:
+		}
+
+		e, err := l.src.WaitEvent(eventWait)
+		switch {
+		case err == nil:
+			l.publish(e)
+		case errors.Is(err, ErrTimeout):
+		default:
+			select {
+			case <-time.After(eventBackoff):
+			case <-l.stop:
+				return
+			}
+		}
+	}
+}
+
+func (l *Listener) publish(e Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for ch := range l.subs {
+		select {
+		case ch <- e:
+		default: // slow subscriber
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package gpu
 
+import "time"
+
 const NO_VALUE int = -0x7FFFFFFF
 
 type Manager interface {
@@ -11,75 +13,165 @@ type Manager interface {
 	GetDriverVersion() string
 }
 
+// Device is one GPU. Tuning knobs are not methods: a device declares them
+// through ParamSource, see ParamDef.
 type Device interface {
 	GetIndex() int
 	GetName() string
 	GetUUID() string
+	GetPciInfo() (PciInfo, error)
+	GetBoardPartNumber() (string, error)
 	GetUtil() (int, int, error)        // gpu, mem
 	GetClocks() (int, int, error)      // gpu, mem; MHz
 	GetMemory() (int, int, int, error) // total, free, used; Byte
-	GetPower() (int, error)            // W
+	GetPower() (int, error)            // mW
 	GetTemperature() (int, error)      // celsius
 	GetFanSpeed() (int, int, error)    // %, rpm
+	GetTempThresholds() (TempThresholds, error)
+
+	GetPcieLink() (PcieLink, error)       // current, drops when idle
+	GetPcieLinkMax() (PcieLink, error)    // what device and slot allow
+	GetPcieThroughput() (int, int, error) // tx, rx; KB/s, may take tens of ms
+	GetPcieReplays() (int, error)         // since driver load
+
+	// GetMediaStats reads encoder, decoder, JPEG, optical flow and frame
+	// capture usage. It only fails if the device is lost.
+	GetMediaStats() (MediaStats, error)
+	GetEncoderSessions() ([]EncoderSession, error)
+
+	// GetTelemetry reads all per-tick values at once. Drivers without a bulk
+	// query can return ReadTelemetry(self). An error wrapping ErrGpuLost or
+	// ErrDriverNotLoaded means the device is gone.
+	GetTelemetry() (Telemetry, error)
+
+	// GetSamples returns the driver's buffered samples newer than since, oldest
+	// first, at whatever resolution the driver keeps them.
+	GetSamples(kind SampleKind, since time.Time) ([]Sample, error)
+
+	GetSupportedClocks() (ClockTable, error)
+
+	// P-states are numbered like NVML's, 0 being the fastest.
+	GetPState() (int, error)
+	GetPStates() ([]int, error) // supported, ascending
+
+	// GetHealthReport reads memory error counters and the Xid errors seen
+	// since the driver was initialized. It only fails if the device is lost.
+	GetHealthReport() (HealthReport, error)
+
+	// GetCapabilities reports what this device supports. It is computed on
+	// the first call and cached.
+	GetCapabilities() Capabilities
+}
+
+// SampleKind is a driver sample buffer the Sampler drains. Every kind costs
+// a call per poll, so there are only the ones that get charted.
+type SampleKind int
+
+const (
+	SamplePower    SampleKind = iota // mW
+	SampleUtilGpu                    // %
+	SampleUtilMem                    // %
+	SampleUtilEnc                    // %
+	SampleUtilDec                    // %
+	SampleClockGpu                   // MHz
+	SampleKinds                      // number of kinds
+)
 
-	GetPl() (int, error)            // W
-	GetPlDefault() (int, error)     // W
-	GetCoGpu() (int, error)         // MHz
-	GetCoMem() (int, error)         // MHz
-	GetClGpu() (int, error)         // MHz
-	GetPlLim() (int, int, error)    // min, max
-	GetCoLimGpu() (int, int, error) // min, max
-	GetCoLimMem() (int, int, error) // min, max
-	GetClLimGpu() (int, int, error) // min, max
-
-	CanSetPl() bool
-	SetPl(int) error    // W
-	SetCoGpu(int) error // MHz
-	SetCoMem(int) error // MHz
-	SetClGpu(int) error // set clock limit; MHz
-	ResetPl() error
-	ResetCoGpu() error
-	ResetCoMem() error
-	ResetClGpu() error
+type Sample struct {
+	Time  time.Time
+	Value float64
 }
 
 type MState struct {
-	ManagerName    string
-	ManagerVersion string
-	DriverVersion  string
+	ManagerName    string `json:"manager"`
+	ManagerVersion string `json:"manager_version"`
+	DriverVersion  string `json:"driver_version"`
+}
+
+// DInfo is what only changes with the driver or the applied settings. It is
+// fetched once and refreshed on demand, see FetchInfo.
+type DInfo struct {
+	Index   int            `json:"index"`
+	Name    string         `json:"name"`
+	UUID    string         `json:"uuid"`
+	Pci     PciInfo        `json:"pci"`
+	PcieMax PcieLink       `json:"pcie_max"`
+	Board   string         `json:"board"`  // board part number
+	Clocks  ClockTable     `json:"clocks"` // supported clocks
+	Thermal TempThresholds `json:"thermal"`
+	Caps    Capabilities   `json:"capabilities"` // probed once, see Device.GetCapabilities
+	Params  []ParamDef     `json:"params"`       // tuning parameters, see ParamDef
 }
 
+// Telemetry is what changes all the time, including the currently applied
+// settings. Values a device can't report are invalid.
+type Telemetry struct {
+	UtilGpu       Value      `json:"util_gpu"`         // %
+	UtilMem       Value      `json:"util_mem"`         // %
+	Temp          Value      `json:"temp"`             // Celsius
+	TempMem       Value      `json:"temp_mem"`         // Celsius
+	FanPct        Value      `json:"fan_pct"`          // %
+	FanRPM        Value      `json:"fan_rpm"`          // RPM
+	Power         Value      `json:"power_mw"`         // mW
+	Energy        Value      `json:"energy"`           // mJ, since driver load
+	ThrottlePower Value      `json:"throttle_power"`   // ms held back by the power limit, since driver load
+	ThrottleTemp  Value      `json:"throttle_thermal"` // ms held back by temperature, since driver load
+	ClockGpu      Value      `json:"clock_gpu"`        // MHz
+	ClockMem      Value      `json:"clock_mem"`        // MHz
+	MemTotal      Value      `json:"mem_total"`        // Byte
+	MemUsed       Value      `json:"mem_used"`         // Byte
+	PcieLink      PcieLink   `json:"pcie_link"`
+	PcieTx        Value      `json:"pcie_tx"` // KB/s
+	PcieRx        Value      `json:"pcie_rx"` // KB/s
+	PcieReplays   Value      `json:"pcie_replays"`
+	PState        Value      `json:"pstate"` // current P-state
+	Media         MediaStats `json:"media"`
+	// Settings holds the current values of the params, by ID. Drivers fill
+	// in what they read anyway, FetchTelemetry the rest.
+	Settings map[string]Value `json:"settings,omitempty"`
+}
+
+// DState is a device's static info plus the telemetry read on every poll.
 type DState struct {
-	Index    int
-	Name     string
-	UUID     string
-	UtilGpu  int // %
-	UtilMem  int // %
-	Temp     int // Celsius
-	FanPct   int // %
-	FanRPM   int // RPM
-	Power    int // W
-	PowerLim int // W
-	ClockGpu int // MHz
-	ClockMem int // MHz
-	MemTotal int // Byte
-	MemUsed  int // Byte
-	CoGpu    int // MHz
-	CoMem    int // MHz
-	ClGpu    int // MHz
-	Limits   Limits
-	Defaults Defaults
+	DInfo
+	Telemetry
+	Health Health `json:"health"`
+}
+
+type Health int
+
+const (
+	HealthOK       Health = iota
+	HealthDegraded        // slow to answer or some queries failing
+	HealthLost            // fell off the bus, or the driver went away
+)
+
+func (h Health) MarshalText() ([]byte, error) { return []byte(h.String()), nil }
+
+func (h Health) String() string {
+	switch h {
+	case HealthOK:
+		return "ok"
+	case HealthDegraded:
+		return "degraded"
+	default:
+		return "lost"
+	}
 }
 
-type Limits struct {
-	PlMin, PlMax       int
-	CoGpuMin, CoGpuMax int
-	CoMemMin, CoMemMax int
-	ClGpuMin, ClGpuMax int
+type PciInfo struct {
+	BusID       string `json:"bus_id"`    // e.g. "00000000:01:00.0"
+	DeviceID    uint32 `json:"device_id"` // device id << 16 | vendor id
+	SubsystemID uint32 `json:"subsystem_id"`
 }
 
-type Defaults struct {
-	Pl, CoGpu, CoMem, ClGpu int
+// TempThresholds are the temperatures at which the driver steps in, in
+// Celsius. Those a device doesn't report are invalid.
+type TempThresholds struct {
+	Shutdown Value `json:"shutdown"`
+	Slowdown Value `json:"slowdown"`
+	MemMax   Value `json:"mem_max"` // memory throttles above this
+	GpuMax   Value `json:"gpu_max"` // gpu throttles above this
 }
 
 func (m *MState) FetchOnce(mgr Manager) {
@@ -88,7 +180,16 @@ func (m *MState) FetchOnce(mgr Manager) {
 	m.DriverVersion = mgr.GetDriverVersion()
 }
 
+// FetchOnce reads everything, static info included.
 func (d *DState) FetchOnce(dev Device) {
+	d.FetchInfo(dev)
+	d.FetchTelemetry(dev)
+}
+
+// FetchInfo reads identity, the clock tables and the params with their
+// limits and defaults. That is expensive, so only call this at startup,
+// after applying settings or when asked to.
+func (d *DState) FetchInfo(dev Device) {
 	d.Index = dev.GetIndex()
 	if d.Name == "" {
 		d.Name = dev.GetName()
@@ -96,20 +197,64 @@ func (d *DState) FetchOnce(dev Device) {
 	if d.UUID == "" {
 		d.UUID = dev.GetUUID()
 	}
-	d.UtilGpu, d.UtilMem, _ = dev.GetUtil()
-	d.Temp, _ = dev.GetTemperature()
-	d.FanPct, d.FanRPM, _ = dev.GetFanSpeed()
-	d.Power, _ = dev.GetPower()
-	d.PowerLim, _ = dev.GetPl()
-	d.ClockGpu, d.ClockMem, _ = dev.GetClocks()
-	d.MemTotal, _, d.MemUsed, _ = dev.GetMemory()
-	d.CoGpu, _ = dev.GetCoGpu()
-	d.CoMem, _ = dev.GetCoMem()
-	d.ClGpu, _ = dev.GetClGpu()
-	d.Limits.PlMin, d.Limits.PlMax, _ = dev.GetPlLim()
-	d.Limits.CoGpuMin, d.Limits.CoGpuMax, _ = dev.GetCoLimGpu()
-	d.Limits.CoMemMin, d.Limits.CoMemMax, _ = dev.GetCoLimMem()
-	d.Limits.ClGpuMin, d.Limits.ClGpuMax, _ = dev.GetClLimGpu()
-	d.Defaults.Pl, _ = dev.GetPlDefault()
-	d.Defaults.CoGpu, d.Defaults.CoMem, d.Defaults.ClGpu = 0, 0, d.Limits.ClGpuMax
+	if d.Pci.BusID == "" {
+		d.Pci, _ = dev.GetPciInfo()
+	}
+	if d.Board == "" {
+		d.Board, _ = dev.GetBoardPartNumber()
+	}
+	if d.Caps == nil {
+		d.Caps = dev.GetCapabilities()
+	}
+	d.PcieMax, _ = dev.GetPcieLinkMax()
+	d.Thermal, _ = dev.GetTempThresholds()
+	if d.Clocks.Mem == nil {
+		d.Clocks, _ = dev.GetSupportedClocks()
+	}
+
+	d.Params = buildParams(dev) // may be shared with published snapshots
+	d.Caps = probeParams(dev, d.Caps, d.Params)
+	d.Settings = readStatic(dev, d.Params, d.Settings)
+}
+
+// FetchTelemetry reads the values that change all the time and updates
+// Health. Telemetry is left as it was if the device is lost.
+func (d *DState) FetchTelemetry(dev Device) error {
+	t, err := dev.GetTelemetry()
+	if !IsLost(err) {
+		t.Settings = readSettings(dev, d.Params, t.Settings, d.Settings)
+	}
+	switch {
+	case err == nil:
+		d.Telemetry, d.Health = t, HealthOK
+	case IsLost(err):
+		d.Health = HealthLost
+	default:
+		d.Telemetry, d.Health = t, HealthDegraded
+	}
+	return err
+}
+
+// ReadTelemetry fills Telemetry through the individual getters. It only
+// fails if the device is lost.
+func ReadTelemetry(dev Device) (Telemetry, error) {
+	var t Telemetry
+	ug, um, err := dev.GetUtil()
+	if IsLost(err) {
+		return t, err
+	}
+	t.UtilGpu, t.UtilMem = UnitPercent.ReadPair(ug, um, err)
+	t.Temp = UnitCelsius.Read(dev.GetTemperature())
+	pct, rpm, err := dev.GetFanSpeed()
+	t.FanPct, t.FanRPM = UnitPercent.Read(pct, err), UnitRPM.Read(rpm, err)
+	t.Power = UnitMilliWatt.Read(dev.GetPower())
+	t.ClockGpu, t.ClockMem = UnitMHz.ReadPair(dev.GetClocks())
+	total, _, used, err := dev.GetMemory()
+	t.MemTotal, t.MemUsed = UnitByte.ReadPair(total, used, err)
+	t.PState = UnitNone.Read(dev.GetPState())
+	t.PcieLink, _ = dev.GetPcieLink()
+	t.PcieTx, t.PcieRx = UnitKBps.ReadPair(dev.GetPcieThroughput())
+	t.PcieReplays = UnitNone.Read(dev.GetPcieReplays())
+	t.Media, _ = dev.GetMediaStats()
+	return t, nil
 }
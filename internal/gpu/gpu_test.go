@@ -0,0 +1,56 @@
+package gpu_test
+
+import (
+	"testing"
+
+	"nvtuner-go/internal/gpu"
+	"nvtuner-go/internal/gpu/gputest"
+)
+
+// Static info is read once; a tick must only read telemetry.
+func TestFetchTelemetryCalls(t *testing.T) {
+	dev := gputest.New()
+	var d gpu.DState
+	d.FetchOnce(dev)
+	once := dev.Total()
+
+	dev.ResetCalls()
+	d.FetchTelemetry(dev)
+	tick := dev.Total()
+
+	if tick >= once {
+		t.Fatalf("tick made %d calls, FetchOnce %d", tick, once)
+	}
+	for _, name := range []string{"GetSupportedClocks", "Params", "GetPStates", "GetName", "GetPciInfo"} {
+		if n := dev.Calls[name]; n != 0 {
+			t.Errorf("tick called %s %d times", name, n)
+		}
+	}
+	// the static P2 offset is left to FetchInfo
+	if n, want := dev.Calls["getParam"], len(d.Params)-1; n != want {
+		t.Errorf("tick read %d params, want the %d not static", n, want)
+	}
+	t.Logf("calls: FetchOnce %d, FetchTelemetry %d", once, tick)
+}
+
+func BenchmarkFetchOnce(b *testing.B) {
+	dev := gputest.New()
+	for b.Loop() {
+		var d gpu.DState
+		d.FetchOnce(dev)
+	}
+	b.ReportMetric(float64(dev.Total())/float64(b.N), "calls/op")
+}
+
+// BenchmarkFetchTelemetry counts Device calls per tick through the generic
+// ReadTelemetry; the nvidia driver's field batching is measured there.
+func BenchmarkFetchTelemetry(b *testing.B) {
+	dev := gputest.New()
+	var d gpu.DState
+	d.FetchInfo(dev)
+	dev.ResetCalls()
+	for b.Loop() {
+		d.FetchTelemetry(dev)
+	}
+	b.ReportMetric(float64(dev.Total())/float64(b.N), "calls/op")
+}
@@ -0,0 +1,199 @@
+// Package gputest provides a fake gpu.Device for the tests of the packages
+// built on gpu.
+package gputest
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"nvtuner-go/internal/gpu"
+)
+
+// Device is a gpu.Device with fixed readings that counts how often each
+// method is called. Its params read back what was last set and every write
+// is logged, see Params.
+type Device struct {
+	Index int
+	Name  string
+	UUID  string
+	Pci   gpu.PciInfo
+	Board string
+
+	Calls  map[string]int
+	Values map[string]int   // current values of the params, by ID
+	Fail   map[string]error // params whose Set fails with the error
+	Writes []string         // "id=v" per Set, "id reset" per Reset, in order
+}
+
+// New returns a device at its defaults: a 200 W power limit, no offsets,
+// open clock locks and the default application clocks.
+func New() *Device {
+	return &Device{
+		Name:  "Fake GPU",
+		UUID:  "GPU-fake",
+		Pci:   gpu.PciInfo{BusID: "00000000:01:00.0", DeviceID: 0x268410DE, SubsystemID: 0x88D51043},
+		Board: "900-FAKE",
+		Calls: make(map[string]int),
+		Values: map[string]int{
+			"pl": 200000, "gpu_co": 0, "gpu_cl": 1995, "gpu_cl_min": 210,
+			"app_gpu": 1500, "app_mem": 5001, gpu.PStateParamID("gpu_co", 2): 0,
+		},
+		Fail: make(map[string]error),
+	}
+}
+
+// State fetches everything of dev, like the sampler does at startup.
+func State(dev gpu.Device) gpu.DState {
+	var d gpu.DState
+	d.FetchOnce(dev)
+	return d
+}
+
+// hit counts a call of the calling method.
+func (f *Device) hit() {
+	pc, _, _, _ := runtime.Caller(1)
+	name := runtime.FuncForPC(pc).Name()
+	f.Calls[name[strings.LastIndexByte(name, '.')+1:]]++
+}
+
+// Total is the number of calls since the last ResetCalls.
+func (f *Device) Total() int {
+	n := 0
+	for _, c := range f.Calls {
+		n += c
+	}
+	return n
+}
+
+func (f *Device) ResetCalls() { clear(f.Calls) }
+
+var Clocks = gpu.ClockTable{
+	Mem: []int{405, 5001},
+	Gpu: map[int][]int{405: {210, 405}, 5001: {210, 1005, 1500, 1995}},
+}
+
+func (f *Device) GetIndex() int   { f.hit(); return f.Index }
+func (f *Device) GetName() string { f.hit(); return f.Name }
+func (f *Device) GetUUID() string { f.hit(); return f.UUID }
+func (f *Device) GetPciInfo() (gpu.PciInfo, error) {
+	f.hit()
+	return f.Pci, nil
+}
+func (f *Device) GetBoardPartNumber() (string, error) { f.hit(); return f.Board, nil }
+func (f *Device) GetUtil() (int, int, error)          { f.hit(); return 50, 20, nil }
+func (f *Device) GetClocks() (int, int, error)        { f.hit(); return 1500, 5001, nil }
+func (f *Device) GetMemory() (int, int, int, error) {
+	f.hit()
+	return 8 << 30, 6 << 30, 2 << 30, nil
+}
+func (f *Device) GetPower() (int, error)         { f.hit(); return 150000, nil }
+func (f *Device) GetTemperature() (int, error)   { f.hit(); return 60, nil }
+func (f *Device) GetFanSpeed() (int, int, error) { f.hit(); return 40, 1500, nil }
+func (f *Device) GetTempThresholds() (gpu.TempThresholds, error) {
+	f.hit()
+	c := gpu.UnitCelsius.Of
+	return gpu.TempThresholds{Shutdown: c(95), Slowdown: c(90), MemMax: c(95), GpuMax: c(87)}, nil
+}
+
+func (f *Device) GetPcieLink() (gpu.PcieLink, error) {
+	f.hit()
+	return gpu.PcieLink{Gen: gpu.UnitNone.Of(4), Width: gpu.UnitNone.Of(16)}, nil
+}
+func (f *Device) GetPcieLinkMax() (gpu.PcieLink, error) {
+	f.hit()
+	return gpu.PcieLink{Gen: gpu.UnitNone.Of(4), Width: gpu.UnitNone.Of(16)}, nil
+}
+func (f *Device) GetPcieThroughput() (int, int, error)              { f.hit(); return 1000, 2000, nil }
+func (f *Device) GetPcieReplays() (int, error)                      { f.hit(); return 0, nil }
+func (f *Device) GetMediaStats() (gpu.MediaStats, error)            { f.hit(); return gpu.NoMedia, nil }
+func (f *Device) GetEncoderSessions() ([]gpu.EncoderSession, error) { f.hit(); return nil, nil }
+func (f *Device) GetTelemetry() (gpu.Telemetry, error)              { f.hit(); return gpu.ReadTelemetry(f) }
+func (f *Device) GetSamples(gpu.SampleKind, time.Time) ([]gpu.Sample, error) {
+	f.hit()
+	return nil, gpu.ErrNotSupported
+}
+
+func (f *Device) GetSupportedClocks() (gpu.ClockTable, error) {
+	f.hit()
+	return Clocks, nil
+}
+
+func (f *Device) GetPState() (int, error)                    { f.hit(); return 0, nil }
+func (f *Device) GetPStates() ([]int, error)                 { f.hit(); return []int{0, 2}, nil }
+func (f *Device) GetHealthReport() (gpu.HealthReport, error) { f.hit(); return gpu.HealthReport{}, nil }
+func (f *Device) GetCapabilities() gpu.Capabilities          { f.hit(); return nil }
+
+// Params declares a power limit, a graphics clock lock, application clocks
+// and the offsets of P0 and P2, the latter static. Lock bounds and
+// application clocks are taken as already on the clock table.
+func (f *Device) Params() []gpu.ParamDef {
+	f.hit()
+	mw, mhz := gpu.UnitMilliWatt.Of, gpu.UnitMHz.Of
+	gclks := Clocks.GpuClocks()
+	p := func(def gpu.ParamDef) gpu.ParamDef {
+		id := def.ID
+		def.Get = func(dev gpu.Device) (int, error) { return dev.(*Device).getParam(id) }
+		def.Set = func(dev gpu.Device, v int) error { return dev.(*Device).setParam(id, v) }
+		if def.Kind == gpu.KindLockMax || def.Kind == gpu.KindLockMin {
+			def.Reset = func(dev gpu.Device) error { return dev.(*Device).resetParam(id) }
+		}
+		return def
+	}
+	return []gpu.ParamDef{
+		p(gpu.ParamDef{ID: "pl", Unit: gpu.UnitMilliWatt, Kind: gpu.KindValue,
+			Min: mw(100000), Max: mw(250000), Default: mw(200000), Step: 1000}),
+		p(gpu.ParamDef{ID: "gpu_co", Unit: gpu.UnitMHz, Kind: gpu.KindOffset,
+			Min: mhz(-200), Max: mhz(200), Default: mhz(0), Step: 15, DependsOn: []string{"pl"}}),
+		p(gpu.ParamDef{ID: "gpu_cl", Unit: gpu.UnitMHz, Kind: gpu.KindLockMax,
+			Min: mhz(210), Max: mhz(1995), Default: mhz(1995), Step: 15, Clocks: gclks, DependsOn: []string{"gpu_co"}}),
+		p(gpu.ParamDef{ID: "gpu_cl_min", Unit: gpu.UnitMHz, Kind: gpu.KindLockMin,
+			Min: mhz(210), Max: mhz(1995), Default: mhz(210), Step: 15, Clocks: gclks, DependsOn: []string{"gpu_cl"}}),
+		p(gpu.ParamDef{ID: "app_mem", Unit: gpu.UnitMHz, Kind: gpu.KindAppClock,
+			Min: mhz(405), Max: mhz(5001), Default: mhz(5001), Step: 50,
+			Snap: func(_ gpu.DState, v int, _ func(string) gpu.Value) gpu.Value {
+				if v <= 0 {
+					v = 5001
+				}
+				return mhz(gpu.SnapDown(Clocks.Mem, v))
+			}}),
+		p(gpu.ParamDef{ID: "app_gpu", Unit: gpu.UnitMHz, Kind: gpu.KindAppClock,
+			Min: mhz(210), Max: mhz(1995), Default: mhz(1500), Step: 15, DependsOn: []string{"app_mem"},
+			Snap: func(_ gpu.DState, v int, with func(string) gpu.Value) gpu.Value {
+				if v <= 0 {
+					v = 1500
+				}
+				return mhz(gpu.SnapDown(Clocks.Gpu[with("app_mem").Or(5001)], v))
+			}}),
+		p(gpu.ParamDef{ID: gpu.PStateParamID("gpu_co", 2), Unit: gpu.UnitMHz, Kind: gpu.KindOffset, PState: 2,
+			Min: mhz(-200), Max: mhz(200), Default: mhz(0), Step: 15, DependsOn: []string{"pl"}, Static: true}),
+	}
+}
+
+func (f *Device) getParam(id string) (int, error) {
+	f.hit()
+	return f.Values[id], nil
+}
+
+func (f *Device) setParam(id string, v int) error {
+	f.hit()
+	if err := f.Fail[id]; err != nil {
+		return err
+	}
+	f.Values[id] = v
+	f.Writes = append(f.Writes, fmt.Sprintf("%s=%d", id, v))
+	return nil
+}
+
+// resetParam opens a clock lock bound.
+func (f *Device) resetParam(id string) error {
+	f.hit()
+	if id == "gpu_cl" {
+		f.Values[id] = 1995
+	} else {
+		f.Values[id] = 210
+	}
+	f.Writes = append(f.Writes, id+" reset")
+	return nil
+}
@@ -0,0 +1,121 @@
+package gpu
+
+import (
+	"fmt"
+	"time"
+)
+
+// Flag is a yes/no value a device may not report.
+type Flag int8
+
+const (
+	FlagUnknown Flag = iota
+	FlagOff
+	FlagOn
+)
+
+func FlagOf(b bool) Flag {
+	if b {
+		return FlagOn
+	}
+	return FlagOff
+}
+
+func (f Flag) On() bool { return f == FlagOn }
+
+func (f Flag) MarshalText() ([]byte, error) { return []byte(f.String()), nil }
+
+func (f Flag) String() string {
+	switch f {
+	case FlagOn:
+		return "on"
+	case FlagOff:
+		return "off"
+	default:
+		return "N/A"
+	}
+}
+
+// EccCounts are memory error counts since the driver loaded (volatile) and
+// over the board's lifetime (aggregate).
+type EccCounts struct {
+	VolatileCorrected    Value `json:"volatile_corrected"`
+	VolatileUncorrected  Value `json:"volatile_uncorrected"`
+	AggregateCorrected   Value `json:"aggregate_corrected"`
+	AggregateUncorrected Value `json:"aggregate_uncorrected"`
+}
+
+// Xid is a critical error the driver reported, see NVIDIA's Xid catalog for
+// what the codes mean.
+type Xid struct {
+	Time time.Time `json:"time"`
+	Code int       `json:"code"`
+}
+
+// HealthReport is the memory error state of a device. Counts a device can't
+// report are invalid. Older boards retire pages, newer ones remap rows.
+type HealthReport struct {
+	Ecc        Flag      `json:"ecc"`
+	EccPending Flag      `json:"ecc_pending"` // mode after the next reboot
+	EccCounts  EccCounts `json:"ecc_counts"`
+
+	RetiredSbe     Value `json:"retired_sbe"` // pages retired for multiple single-bit errors
+	RetiredDbe     Value `json:"retired_dbe"` // pages retired for a double-bit error
+	RetiredPending Flag  `json:"retired_pending"`
+
+	RemappedCorrectable   Value `json:"remapped_correctable"`
+	RemappedUncorrectable Value `json:"remapped_uncorrectable"`
+	RemapPending          Flag  `json:"remap_pending"`
+	RemapFailed           Flag  `json:"remap_failed"`
+
+	Xids []Xid `json:"xids"` // since nvtuner started, oldest first
+}
+
+// Problems describes what in the report needs attention.
+func (r HealthReport) Problems() []string {
+	var res []string
+	if n, ok := r.EccCounts.VolatileUncorrected.Get(); ok && n > 0 {
+		res = append(res, fmt.Sprintf("%d uncorrected ECC errors since driver load", n))
+	}
+	if r.RetiredPending.On() {
+		res = append(res, "page retirement pending, reboot to apply")
+	}
+	if r.RemapPending.On() {
+		res = append(res, "row remapping pending, reset the GPU to apply")
+	}
+	if r.RemapFailed.On() {
+		res = append(res, "row remapping failed, the board may need replacing")
+	}
+	if len(r.Xids) > 0 {
+		x := r.Xids[len(r.Xids)-1]
+		res = append(res, fmt.Sprintf("Xid %d at %s", x.Code, x.Time.Format(time.TimeOnly)))
+	}
+	return res
+}
+
+// NewErrors lists what got worse since prev, for logging and alerts. Xids
+// are left out, they arrive as events, see Listener.
+func (r HealthReport) NewErrors(prev HealthReport) []string {
+	var res []string
+	grew := func(what string, now, before Value) {
+		if now.Valid && before.Valid && now.V > before.V {
+			res = append(res, fmt.Sprintf("%d new %s (%d total)", now.V-before.V, what, now.V))
+		}
+	}
+	grew("corrected ECC errors", r.EccCounts.VolatileCorrected, prev.EccCounts.VolatileCorrected)
+	grew("uncorrected ECC errors", r.EccCounts.VolatileUncorrected, prev.EccCounts.VolatileUncorrected)
+	grew("pages retired for single-bit errors", r.RetiredSbe, prev.RetiredSbe)
+	grew("pages retired for double-bit errors", r.RetiredDbe, prev.RetiredDbe)
+	grew("correctable remapped rows", r.RemappedCorrectable, prev.RemappedCorrectable)
+	grew("uncorrectable remapped rows", r.RemappedUncorrectable, prev.RemappedUncorrectable)
+
+	turnedOn := func(what string, now, before Flag) {
+		if now.On() && !before.On() {
+			res = append(res, what)
+		}
+	}
+	turnedOn("page retirement pending", r.RetiredPending, prev.RetiredPending)
+	turnedOn("row remapping pending", r.RemapPending, prev.RemapPending)
+	turnedOn("row remapping failed", r.RemapFailed, prev.RemapFailed)
+	return res
+}
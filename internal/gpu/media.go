@@ -0,0 +1,40 @@
+package gpu
+
+// MediaStats covers the video and image engines: NVENC, NVDEC, NVJPG, the
+// optical flow accelerator and frame buffer capture. Values a device can't
+// report are invalid.
+type MediaStats struct {
+	UtilEnc     Value `json:"util_enc"`     // %
+	UtilDec     Value `json:"util_dec"`     // %
+	UtilJpeg    Value `json:"util_jpeg"`    // %
+	UtilOfa     Value `json:"util_ofa"`     // %
+	EncSessions Value `json:"enc_sessions"` // active encoder sessions
+	EncFps      Value `json:"enc_fps"`      // average over sessions
+	EncLatency  Value `json:"enc_latency"`  // us, average over sessions
+	FbcSessions Value `json:"fbc_sessions"` // active frame buffer capture sessions
+	FbcFps      Value `json:"fbc_fps"`
+	FbcLatency  Value `json:"fbc_latency"` // us
+}
+
+// NoMedia is MediaStats with nothing read.
+var NoMedia = MediaStats{
+	UtilEnc: UnitPercent.NA(nil), UtilDec: UnitPercent.NA(nil), UtilJpeg: UnitPercent.NA(nil), UtilOfa: UnitPercent.NA(nil),
+	EncSessions: UnitNone.NA(nil), EncFps: UnitNone.NA(nil), EncLatency: UnitMicroSecond.NA(nil),
+	FbcSessions: UnitNone.NA(nil), FbcFps: UnitNone.NA(nil), FbcLatency: UnitMicroSecond.NA(nil),
+}
+
+// HasCodec tells if the device reports encoder or decoder utilization.
+func (m MediaStats) HasCodec() bool {
+	return m.UtilEnc.Valid || m.UtilDec.Valid
+}
+
+// EncoderSession is one process using NVENC.
+type EncoderSession struct {
+	ID      int    `json:"id"`
+	Pid     int    `json:"pid"`
+	Codec   string `json:"codec"` // "H.264", "HEVC", "AV1"
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Fps     int    `json:"fps"`
+	Latency int    `json:"latency"` // us
+}
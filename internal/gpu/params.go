@@ -0,0 +1,262 @@
+package gpu
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParamKind tells how a parameter's value is read: which values leave the
+// driver's default in place and whether it snaps to the clock tables.
+type ParamKind int
+
+const (
+	KindValue    ParamKind = iota // plain setting within Min and Max
+	KindOffset                    // clock offset, 0 is stock
+	KindLockMax                   // upper clock lock bound; <= 0 or >= Max is open
+	KindLockMin                   // lower clock lock bound; <= Min is open
+	KindAppClock                  // application clock; <= 0 is the default
+	KindTarget                    // driver target; <= 0 keeps what is set
+)
+
+func (k ParamKind) String() string {
+	switch k {
+	case KindOffset:
+		return "offset"
+	case KindLockMax:
+		return "lock_max"
+	case KindLockMin:
+		return "lock_min"
+	case KindAppClock:
+		return "app_clock"
+	case KindTarget:
+		return "target"
+	default:
+		return "value"
+	}
+}
+
+func (k ParamKind) MarshalText() ([]byte, error) { return []byte(k.String()), nil }
+
+// ParamDef describes a tuning parameter of a device. The UI, config check,
+// planner and CLI only go through these, so a driver adds a knob by
+// returning one from ParamSource instead of growing Device.
+//
+// Values are ints in Unit. Limits and the default are read when the entry
+// is built, see DState.FetchInfo. The funcs get the Device to act on rather
+// than capturing one, as a device may be re-created under its params.
+type ParamDef struct {
+	ID         string    `json:"id"`    // config key and capability ID
+	Label      string    `json:"label"` // like "POWER LIMIT"
+	ShortLabel string    `json:"short"` // like "PL", at most 4 characters
+	Unit       Unit      `json:"unit"`
+	Kind       ParamKind `json:"kind"`
+	PState     int       `json:"pstate,omitempty"` // set for offsets of P-states but P0
+	Min        Value     `json:"min"`
+	Max        Value     `json:"max"`
+	Default    Value     `json:"default"`
+	Step       int       `json:"step"`                 // for stepping through values in Unit
+	DependsOn  []string  `json:"depends_on,omitempty"` // IDs to apply before this one
+	Clocks     []int     `json:"-"`                    // supported clocks, for lock bounds
+
+	// Static params only change when settings are applied, so FetchInfo
+	// reads them rather than every poll, see Telemetry.Settings.
+	Static bool `json:"-"`
+
+	Get func(dev Device) (int, error) `json:"-"`
+	Set func(dev Device, v int) error `json:"-"`
+	// Reset is used when rolling back to an unknown previous value.
+	Reset func(dev Device) error `json:"-"`
+	// Snap is set for params the driver moves onto supported values. It maps
+	// a config value to what the driver will set and read back, so such
+	// params are not range checked. Invalid if that's unknown. with tells
+	// what the params in DependsOn are set to alongside.
+	Snap func(d DState, v int, with func(id string) Value) Value `json:"-"`
+}
+
+// ParamSource is implemented by devices with tuning parameters, which is
+// how a driver declares all of them, power limit and clocks included.
+// Params is called on every FetchInfo, so limits and defaults can be read
+// fresh.
+type ParamSource interface {
+	Params() []ParamDef
+}
+
+// Current is the value the device has now.
+func (p ParamDef) Current(d DState) Value {
+	if v, ok := d.Settings[p.ID]; ok {
+		return v
+	}
+	return p.Unit.NA(nil)
+}
+
+// Setting is the current value of the param with the given ID, invalid if
+// the device has none.
+func (d DState) Setting(id string) Value {
+	if p, ok := d.Param(id); ok {
+		return p.Current(d)
+	}
+	return Value{}
+}
+
+// Range returns the device's limits, ok is false if it doesn't report them.
+func (p ParamDef) Range() (lo, hi int, ok bool) {
+	return p.Min.V, p.Max.V, p.Min.Valid && p.Max.Valid
+}
+
+// Unset tells if v leaves the param at the device's default: an open clock
+// lock bound, or application clocks and targets <= 0. Without limits a
+// lock's upper bound can't be told open.
+func (p ParamDef) Unset(v int) bool {
+	lo, hi, ok := p.Range()
+	switch p.Kind {
+	case KindLockMax:
+		return ok && (v <= 0 || v >= hi)
+	case KindLockMin:
+		return v <= lo
+	case KindAppClock, KindTarget:
+		return v <= 0
+	default:
+		return false
+	}
+}
+
+// SnapLock moves a clock lock bound onto the supported clocks; an open bound
+// becomes the end of the table, which is also what it reads back as. Other
+// kinds, or devices without limits, are returned as they are.
+func (p ParamDef) SnapLock(v int) int {
+	lo, hi, ok := p.Range()
+	switch {
+	case !ok || p.Kind != KindLockMax && p.Kind != KindLockMin:
+		return v
+	case p.Unset(v) && p.Kind == KindLockMin:
+		return lo
+	case p.Unset(v):
+		return hi
+	case p.Kind == KindLockMin:
+		return SnapUp(p.Clocks, v)
+	default:
+		return SnapDown(p.Clocks, v)
+	}
+}
+
+// DisplayUnit is the unit values are shown and typed in, W for power.
+func (p ParamDef) DisplayUnit() string {
+	u, _ := p.Unit.Display()
+	return string(u)
+}
+
+// Format renders v in DisplayUnit.
+func (p ParamDef) Format(v int) string {
+	if _, scale := p.Unit.Display(); scale > 1 {
+		return strconv.FormatFloat(float64(v)/float64(scale), 'f', -1, 64)
+	}
+	return strconv.Itoa(v)
+}
+
+// FormatValue is Format, or "N/A" if v is invalid.
+func (p ParamDef) FormatValue(v Value) string {
+	if !v.Valid {
+		return "N/A"
+	}
+	return p.Format(v.V)
+}
+
+// Parse reads a value typed in DisplayUnit; scaled units take fractions.
+func (p ParamDef) Parse(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	_, scale := p.Unit.Display()
+	if scale <= 1 {
+		return strconv.Atoi(s)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return int(math.Round(f * float64(scale))), nil
+}
+
+// Param returns the entry with the given ID.
+func (d DInfo) Param(id string) (ParamDef, bool) {
+	for _, p := range d.Params {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return ParamDef{}, false
+}
+
+// buildParams lists the params the device declares. Clock lock bounds
+// snap onto their clocks unless the driver says otherwise.
+func buildParams(dev Device) []ParamDef {
+	src, ok := dev.(ParamSource)
+	if !ok {
+		return nil
+	}
+	ps := src.Params()
+	for i, p := range ps {
+		if p.Clocks != nil && p.Snap == nil {
+			ps[i].Snap = func(_ DState, v int, _ func(string) Value) Value { return p.Unit.Of(p.SnapLock(v)) }
+		}
+	}
+	return ps
+}
+
+// probeParams adds a capability for every param the device's own probe
+// left out. What writes need is up to the driver, so those with a Set are
+// taken as writable.
+func probeParams(dev Device, caps Capabilities, params []ParamDef) Capabilities {
+	for _, p := range params {
+		if caps.Has(p.ID) {
+			continue
+		}
+		readErr, writeErr := error(ErrNotSupported), error(ErrNotSupported)
+		if p.Get != nil {
+			_, readErr = p.Get(dev)
+		}
+		if p.Set != nil {
+			writeErr = nil
+		}
+		caps = append(caps, ProbeParam(p.ID, readErr, writeErr))
+	}
+	return caps
+}
+
+// readStatic reads the static params into a copy of settings, which may be
+// shared with published snapshots.
+func readStatic(dev Device, params []ParamDef, settings map[string]Value) map[string]Value {
+	m := maps.Clone(settings)
+	for _, p := range params {
+		if !p.Static || p.Get == nil {
+			continue
+		}
+		if m == nil {
+			m = make(map[string]Value)
+		}
+		m[p.ID] = p.Unit.Read(p.Get(dev))
+	}
+	return m
+}
+
+// readSettings adds the values of the params the driver's telemetry left
+// out: static ones carry over from prev, the others are read through Get.
+// Errors leave them invalid.
+func readSettings(dev Device, params []ParamDef, got, prev map[string]Value) map[string]Value {
+	m := got
+	for _, p := range params {
+		if _, ok := m[p.ID]; ok || p.Get == nil {
+			continue
+		}
+		if m == nil {
+			m = make(map[string]Value, len(params))
+		}
+		if v, ok := prev[p.ID]; ok && p.Static {
+			m[p.ID] = v
+		} else {
+			m[p.ID] = p.Unit.Read(p.Get(dev))
+		}
+	}
+	return m
+}
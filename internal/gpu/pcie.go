@@ -0,0 +1,34 @@
+package gpu
+
+import "fmt"
+
+// PcieLoadUtil is the GPU utilization above which a link running below its
+// maximum is worth a warning; idle GPUs train the link down to save power.
+const PcieLoadUtil = 50
+
+type PcieLink struct {
+	Gen   Value `json:"gen"`
+	Width Value `json:"width"` // lanes
+}
+
+func (l PcieLink) String() string {
+	if !l.Valid() {
+		return "N/A"
+	}
+	return fmt.Sprintf("Gen%d x%d", l.Gen.V, l.Width.V)
+}
+
+// Valid tells if both the generation and the width were read.
+func (l PcieLink) Valid() bool {
+	return l.Gen.Valid && l.Width.Valid
+}
+
+// PcieBelowMax tells if the link runs slower than it could while the GPU is
+// busy, like a card stuck at Gen1 x4.
+func (d DState) PcieBelowMax() bool {
+	cur, top := d.PcieLink, d.PcieMax
+	if util, ok := d.UtilGpu.Get(); !ok || util < PcieLoadUtil || !cur.Valid() || !top.Valid() {
+		return false
+	}
+	return cur.Gen.V < top.Gen.V || cur.Width.V < top.Width.V
+}
@@ -0,0 +1,23 @@
+package gpu
+
+import (
+	"math"
+	"strconv"
+)
+
+// Power is carried in milliwatts, as NVML reports it. These convert where
+// people read or type watts.
+
+func Watts(mw int) float64 { return float64(mw) / 1000 }
+
+// MilliWatts rounds to the nearest milliwatt.
+func MilliWatts(w float64) int { return int(math.Round(w * 1000)) }
+
+// FormatWatts renders mw in watts with at most one decimal, like "87.5",
+// or "N/A".
+func FormatWatts(mw int) string {
+	if mw == NO_VALUE {
+		return "N/A"
+	}
+	return strconv.FormatFloat(math.Round(float64(mw)/100)/10, 'f', -1, 64)
+}
@@ -0,0 +1,317 @@
+package gpu
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrSampleTimeout = fmt.Errorf("%w: device did not answer in time", ErrTimeout)
+
+var ErrSamplerStopped = errors.New("sampler stopped")
+
+// Snapshot is one immutable reading of a device published by a Sampler.
+type Snapshot struct {
+	Slot    int                   // position in the device list given to NewSampler
+	State   DState                // last good state; unchanged from the previous snapshot on error
+	Time    time.Time             // when State was read
+	Samples [SampleKinds][]Sample // driver samples since the previous snapshot
+	Sampled [SampleKinds]bool     // kinds the device buffers; chart Samples, not State, for these
+	Err     error
+}
+
+// Sampler polls every device from its own goroutine so that slow or hung
+// driver calls never block the consumer.
+type Sampler struct {
+	mgr      Manager
+	devs     []Device // current handles; written under drvMu, see Do
+	interval time.Duration
+	timeout  time.Duration
+
+	// drvMu is held for reading by every device call and for writing while
+	// the driver is re-initialized, which invalidates all handles.
+	drvMu   sync.RWMutex
+	lost    chan int      // slots whose device is gone, for the rebinder
+	handles []chan Device // new handles, one pending per slot
+	refresh []atomic.Bool
+	out     chan Snapshot
+	stop    chan struct{}
+	loops   sync.WaitGroup
+	fetches sync.WaitGroup
+	once    sync.Once
+
+	stopMu  sync.Mutex // orders Do's fetches.Add before Stop's Wait
+	stopped bool
+}
+
+// rebindInterval is how often a lost device is looked for again, per UUID.
+const rebindInterval = 5 * time.Second
+
+// NewSampler starts polling devs every interval. A poll that takes longer
+// than timeout publishes a snapshot with ErrSampleTimeout; no new poll is
+// issued for that device until the hung one returns. Lost devices are
+// re-enumerated through mgr and re-bound by UUID; the Sampler owns the
+// driver while it runs and re-initializes it if it went away.
+func NewSampler(mgr Manager, devs []Device, initial []DState, interval, timeout time.Duration) *Sampler {
+	s := &Sampler{
+		mgr:      mgr,
+		devs:     slices.Clone(devs),
+		interval: interval,
+		timeout:  timeout,
+		lost:     make(chan int, len(devs)),
+		handles:  make([]chan Device, len(devs)),
+		refresh:  make([]atomic.Bool, len(devs)),
+		out:      make(chan Snapshot, 2*len(devs)),
+		stop:     make(chan struct{}),
+	}
+	for i := range devs {
+		s.handles[i] = make(chan Device, 1)
+		s.loops.Add(1)
+		go s.run(i, devs[i], initial[i])
+	}
+	s.fetches.Add(1) // may wait for a hung call, like fetches
+	go s.rebinder()
+	return s
+}
+
+func (s *Sampler) C() <-chan Snapshot { return s.out }
+
+// Refresh makes the next poll of a device re-read its static info as well,
+// e.g. after settings were applied.
+func (s *Sampler) Refresh(slot int) {
+	s.refresh[slot].Store(true)
+}
+
+// Do runs fn with the slot's current handle while the driver can't be
+// re-initialized under it. Device calls outside the polling, like applying
+// settings, must go through Do rather than hold on to a handle. It returns
+// fn's error.
+func (s *Sampler) Do(slot int, fn func(dev Device) error) error {
+	s.stopMu.Lock()
+	if s.stopped {
+		s.stopMu.Unlock()
+		return ErrSamplerStopped
+	}
+	s.fetches.Add(1)
+	s.stopMu.Unlock()
+	defer s.fetches.Done()
+
+	s.drvMu.RLock()
+	defer s.drvMu.RUnlock()
+	return fn(s.devs[slot])
+}
+
+// Stop ends polling and waits, at most one timeout, for in-flight calls so
+// that the driver can be shut down afterwards.
+func (s *Sampler) Stop() {
+	s.once.Do(func() {
+		s.stopMu.Lock()
+		s.stopped = true
+		s.stopMu.Unlock()
+		close(s.stop)
+		s.loops.Wait()
+
+		done := make(chan struct{})
+		go func() {
+			s.fetches.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(s.timeout):
+		}
+	})
+}
+
+// poll is the outcome of one background read.
+type poll struct {
+	state   DState
+	samples [SampleKinds][]Sample
+	cursors [SampleKinds]time.Time
+	off     [SampleKinds]bool // buffers the device doesn't keep
+	err     error
+}
+
+func (s *Sampler) run(slot int, dev Device, state DState) {
+	defer s.loops.Done()
+
+	last := time.Now()
+	var cursors [SampleKinds]time.Time
+	var off [SampleKinds]bool
+	rebound := false
+	tick := time.NewTicker(s.interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case d := <-s.handles[slot]:
+			dev, rebound = d, true
+			cursors, off = [SampleKinds]time.Time{}, [SampleKinds]bool{}
+		default:
+		}
+
+		full := s.refresh[slot].Swap(false) || rebound
+		done := make(chan poll, 1)
+		s.fetches.Add(1)
+		go func(dev Device, p poll) {
+			defer s.fetches.Done()
+			s.drvMu.RLock()
+			defer s.drvMu.RUnlock()
+			if full {
+				p.state.FetchInfo(dev)
+			}
+			p.err = p.state.FetchTelemetry(dev)
+			if p.state.Health != HealthLost {
+				drainSamples(dev, &p)
+			}
+			done <- p
+		}(dev, poll{state: state, cursors: cursors, off: off})
+
+		timer := time.NewTimer(s.timeout)
+		var p poll
+		select {
+		case p = <-done:
+		case <-timer.C:
+			slow := state
+			slow.Health = HealthDegraded
+			s.publish(Snapshot{Slot: slot, State: slow, Time: last, Err: ErrSampleTimeout})
+			select {
+			case p = <-done:
+			case <-s.stop:
+				return
+			}
+		case <-s.stop:
+			timer.Stop()
+			return
+		}
+		timer.Stop()
+
+		state, cursors, off = p.state, p.cursors, p.off
+		if state.Health != HealthLost {
+			last = time.Now()
+		}
+		snap := Snapshot{Slot: slot, State: state, Time: last, Samples: p.samples, Err: p.err}
+		for k := range SampleKinds {
+			snap.Sampled[k] = !off[k]
+		}
+		s.publish(snap)
+
+		rebound = false
+		if state.Health == HealthLost {
+			select {
+			case s.lost <- slot:
+			default: // already queued
+			}
+		}
+
+		select {
+		case <-tick.C:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// rebinder re-binds the slots reported lost, looking for each UUID at most
+// once per rebindInterval.
+func (s *Sampler) rebinder() {
+	defer s.fetches.Done()
+	lost := make(map[int]bool)
+	next := make(map[string]time.Time) // by UUID
+	for {
+		select {
+		case slot := <-s.lost:
+			lost[slot] = true
+		case <-s.stop:
+			return
+		}
+
+		now, due := time.Now(), false
+		for slot := range lost {
+			if uuid := s.devs[slot].GetUUID(); !now.Before(next[uuid]) {
+				next[uuid], due = now.Add(rebindInterval), true
+			}
+		}
+		if due {
+			s.rebind(lost)
+		}
+	}
+}
+
+// rebind enumerates the devices once and hands the lost slots their new
+// handle, re-initializing the driver first if it went away. That
+// invalidates every handle, so then all slots get a new one.
+func (s *Sampler) rebind(lost map[int]bool) {
+	s.drvMu.Lock()
+	defer s.drvMu.Unlock()
+	byUUID, reinit, err := Enumerate(s.mgr)
+	if err != nil {
+		return
+	}
+
+	for slot, dev := range s.devs {
+		d, ok := byUUID[dev.GetUUID()]
+		if !ok || !reinit && !lost[slot] {
+			continue
+		}
+		select { // replace a handle the slot hasn't picked up yet
+		case <-s.handles[slot]:
+		default:
+		}
+		s.handles[slot] <- d
+		s.devs[slot] = d
+		delete(lost, slot)
+	}
+}
+
+// Enumerate lists mgr's devices by UUID, re-initializing mgr first if the
+// driver went away. reinit tells if it did, which invalidates every handle
+// from before.
+func Enumerate(mgr Manager) (byUUID map[string]Device, reinit bool, err error) {
+	devs, err := mgr.Devices()
+	if reinit = IsLost(err); reinit {
+		mgr.Shutdown()
+		if err = mgr.Init(); err == nil {
+			devs, err = mgr.Devices()
+		}
+	}
+	if err != nil {
+		return nil, reinit, err
+	}
+	byUUID = make(map[string]Device, len(devs))
+	for _, d := range devs {
+		byUUID[d.GetUUID()] = d
+	}
+	return byUUID, reinit, nil
+}
+
+// drainSamples reads every sample buffer past its cursor and moves the
+// cursors to the newest sample seen. A kind is given up on once retrying
+// can't help, e.g. the device doesn't keep it.
+func drainSamples(dev Device, p *poll) {
+	for k := range SampleKinds {
+		if p.off[k] {
+			continue
+		}
+		got, err := dev.GetSamples(k, p.cursors[k])
+		if err != nil {
+			p.off[k] = !IsRetryable(err)
+			continue
+		}
+		if len(got) == 0 {
+			continue
+		}
+		p.samples[k] = got
+		p.cursors[k] = got[len(got)-1].Time
+	}
+}
+
+func (s *Sampler) publish(snap Snapshot) {
+	select {
+	case s.out <- snap:
+	case <-s.stop:
+	}
+}
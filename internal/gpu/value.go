@@ -0,0 +1,110 @@
+package gpu
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Unit string
+
+const (
+	UnitNone        Unit = ""
+	UnitPercent     Unit = "%"
+	UnitCelsius     Unit = "°C"
+	UnitMHz         Unit = "MHz"
+	UnitMilliWatt   Unit = "mW"
+	UnitMilliJoule  Unit = "mJ"
+	UnitByte        Unit = "B"
+	UnitKBps        Unit = "KB/s"
+	UnitRPM         Unit = "RPM"
+	UnitMilliSecond Unit = "ms"
+	UnitMicroSecond Unit = "us"
+)
+
+// Display returns the unit people read and type, and how many of u make
+// one of it: power is carried in mW but shown in W.
+func (u Unit) Display() (Unit, int) {
+	switch u {
+	case UnitMilliWatt:
+		return "W", 1000
+	case UnitMilliJoule:
+		return "J", 1000
+	default:
+		return u, 1
+	}
+}
+
+// Value is a reading or setting that may be unknown. The zero Value is
+// invalid, so fields nobody filled in render as N/A rather than 0.
+type Value struct {
+	V     int
+	Unit  Unit
+	Valid bool
+	Err   error // why it is invalid, nil if it was never read
+}
+
+// Of makes a valid value.
+func (u Unit) Of(v int) Value { return Value{V: v, Unit: u, Valid: true} }
+
+// Read makes a value out of a getter's result; it is invalid if the getter
+// failed or returned NO_VALUE.
+func (u Unit) Read(v int, err error) Value {
+	if err != nil || v == NO_VALUE {
+		return Value{Unit: u, Err: err}
+	}
+	return u.Of(v)
+}
+
+// ReadPair is Read for getters returning two values, like min and max.
+func (u Unit) ReadPair(a, b int, err error) (Value, Value) {
+	return u.Read(a, err), u.Read(b, err)
+}
+
+// NA is an invalid value of unit u.
+func (u Unit) NA(err error) Value { return Value{Unit: u, Err: err} }
+
+func (v Value) Get() (int, bool) { return v.V, v.Valid }
+
+// Or returns the value, or def if it is invalid.
+func (v Value) Or(def int) int {
+	if !v.Valid {
+		return def
+	}
+	return v.V
+}
+
+// Float is the value as a float64 for charts, false if invalid.
+func (v Value) Float() (float64, bool) { return float64(v.V), v.Valid }
+
+func (v Value) String() string {
+	switch {
+	case !v.Valid:
+		return "N/A"
+	case v.Unit == UnitMilliWatt:
+		return FormatWatts(v.V) + " W"
+	case v.Unit == UnitNone:
+		return fmt.Sprint(v.V)
+	default:
+		return fmt.Sprintf("%d %s", v.V, v.Unit)
+	}
+}
+
+// MarshalJSON writes the number, or null if invalid; units are in the keys.
+func (v Value) MarshalJSON() ([]byte, error) {
+	if !v.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(v.V)
+}
+
+func (v *Value) UnmarshalJSON(b []byte) error {
+	var n *int
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	v.Valid, v.Err = n != nil, nil
+	if n != nil {
+		v.V = *n
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nvtuner-go/internal/config"
+	"nvtuner-go/internal/gpu"
+
+	"gopkg.in/yaml.v3"
+)
+
+const FormatVersion = 1
+
+// Profile is a portable snapshot of tuning settings. It records enough about
+// the exporting machine to remap and sanity-check the values elsewhere.
+type Profile struct {
+	Version        int       `json:"version" yaml:"version"`
+	Exported       time.Time `json:"exported" yaml:"exported"`
+	Manager        string    `json:"manager" yaml:"manager"`
+	ManagerVersion string    `json:"manager_version" yaml:"manager_version"`
+	DriverVersion  string    `json:"driver_version" yaml:"driver_version"`
+	Devices        []Device  `json:"devices" yaml:"devices"`
+}
+
+type Device struct {
+	Index    int                `json:"index" yaml:"index"`
+	UUID     string             `json:"uuid" yaml:"uuid"`
+	Model    string             `json:"model" yaml:"model"`
+	PciID    string             `json:"pci_id,omitempty" yaml:"pci_id,omitempty"`
+	Board    string             `json:"board,omitempty" yaml:"board,omitempty"`
+	Limits   map[string]Range   `json:"limits" yaml:"limits"`
+	Settings config.GpuSettings `json:"settings" yaml:"settings"`
+}
+
+type Range struct {
+	Min int `json:"min" yaml:"min"`
+	Max int `json:"max" yaml:"max"`
+}
+
+type Format int
+
+const (
+	JSON Format = iota
+	YAML
+)
+
+// FormatOf guesses the format from a file name, defaulting to JSON.
+func FormatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return YAML
+	default:
+		return JSON
+	}
+}
+
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "json":
+		return JSON, nil
+	case "yaml", "yml":
+		return YAML, nil
+	default:
+		return JSON, fmt.Errorf("unknown format %q", s)
+	}
+}
+
+// Export builds a profile from the settings currently resolved for each device.
+func Export(ms gpu.MState, states []gpu.DState, cfg *config.Manager) Profile {
+	p := Profile{
+		Version:        FormatVersion,
+		Exported:       time.Now().UTC().Truncate(time.Second),
+		Manager:        ms.ManagerName,
+		ManagerVersion: ms.ManagerVersion,
+		DriverVersion:  ms.DriverVersion,
+	}
+	for _, d := range states {
+		s, _, _ := cfg.Resolve(config.IdentityOf(d))
+		lim := make(map[string]Range, len(d.Params))
+		for _, p := range d.Params {
+			if lo, hi, ok := p.Range(); ok { // unreported limits are left out
+				lim[p.ID] = Range{lo, hi}
+			}
+		}
+		p.Devices = append(p.Devices, Device{
+			Index:    d.Index,
+			UUID:     d.UUID,
+			Model:    d.Name,
+			PciID:    fmt.Sprintf("0x%08X", d.Pci.DeviceID),
+			Board:    d.Board,
+			Limits:   lim,
+			Settings: s,
+		})
+	}
+	return p
+}
+
+func (p Profile) Marshal(f Format) ([]byte, error) {
+	if f == YAML {
+		return yaml.Marshal(p)
+	}
+	return json.MarshalIndent(p, "", "    ")
+}
+
+func Unmarshal(data []byte, f Format) (Profile, error) {
+	var p Profile
+	var err error
+	if f == YAML {
+		err = yaml.Unmarshal(data, &p)
+	} else {
+		err = json.Unmarshal(data, &p)
+	}
+	if err != nil {
+		return p, err
+	}
+	if p.Version > FormatVersion {
+		return p, fmt.Errorf("profile version %d is newer than supported %d", p.Version, FormatVersion)
+	}
+	return p, nil
+}
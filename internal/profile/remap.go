@@ -0,0 +1,89 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+
+	"nvtuner-go/internal/config"
+	"nvtuner-go/internal/gpu"
+)
+
+// Mapping pairs a local device with the profile entry chosen for it.
+type Mapping struct {
+	Local    gpu.DState
+	Entry    Device
+	By       string // "uuid" or "model"
+	Current  config.GpuSettings
+	Warnings []string
+}
+
+// Remap assigns profile entries to local devices: same UUID first, then the
+// next unused entry of the same model. Local devices without a match are
+// left out.
+func (p Profile) Remap(states []gpu.DState, cfg *config.Manager) []Mapping {
+	used := make([]bool, len(p.Devices))
+	var res []Mapping
+
+	for _, d := range states {
+		idx, by := -1, ""
+		for i, e := range p.Devices {
+			if !used[i] && e.UUID == d.UUID {
+				idx, by = i, "uuid"
+				break
+			}
+		}
+		if idx < 0 {
+			for i, e := range p.Devices {
+				if !used[i] && strings.EqualFold(e.Model, d.Name) {
+					idx, by = i, "model"
+					break
+				}
+			}
+		}
+		if idx < 0 {
+			continue
+		}
+		used[idx] = true
+
+		cur, _, _ := cfg.Resolve(config.IdentityOf(d))
+		res = append(res, Mapping{
+			Local:    d,
+			Entry:    p.Devices[idx],
+			By:       by,
+			Current:  cur,
+			Warnings: rangeWarnings(p.Devices[idx].Settings, d),
+		})
+	}
+	return res
+}
+
+func rangeWarnings(s config.GpuSettings, d gpu.DState) []string {
+	var res []string
+	for _, p := range d.Params {
+		v := s.Get(p.ID)
+		lo, hi, ok := p.Range()
+		if !ok {
+			continue
+		}
+		if p.Unset(v) {
+			continue // device default
+		}
+		if v < lo || v > hi {
+			res = append(res, fmt.Sprintf("%s %d out of range [%d, %d]", p.ID, v, lo, hi))
+		}
+	}
+	return res
+}
+
+// Diff lists the fields that importing would change, as "name: old -> new".
+func (m Mapping) Diff() []string {
+	var res []string
+	cur, next := m.Current, m.Entry.Settings
+	for _, p := range m.Local.Params {
+		a, b := cur.Get(p.ID), next.Get(p.ID)
+		if a != b {
+			res = append(res, fmt.Sprintf("%s: %d -> %d", p.ID, a, b))
+		}
+	}
+	return res
+}
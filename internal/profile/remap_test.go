@@ -0,0 +1,97 @@
+package profile
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"nvtuner-go/internal/config"
+	"nvtuner-go/internal/gpu"
+	"nvtuner-go/internal/gpu/gputest"
+)
+
+func state(index int, uuid, name string) gpu.DState {
+	dev := gputest.New()
+	dev.Index, dev.UUID, dev.Name = index, uuid, name
+	return gputest.State(dev)
+}
+
+func TestRemap(t *testing.T) {
+	a := state(0, "GPU-a", "RTX 4090")
+	b := state(1, "GPU-b", "RTX 4090")
+	c := state(2, "GPU-c", "RTX 3080")
+	tests := []struct {
+		name    string
+		states  []gpu.DState
+		entries []Device // only UUID and Model matter
+		want    []string // "local UUID <- entry index by how"
+	}{
+		{"same uuid", []gpu.DState{a, b},
+			[]Device{{UUID: "GPU-b", Model: "RTX 4090"}, {UUID: "GPU-a", Model: "RTX 4090"}},
+			[]string{"GPU-a <- 1 by uuid", "GPU-b <- 0 by uuid"}},
+		{"same model, in order", []gpu.DState{a, b},
+			[]Device{{UUID: "GPU-x", Model: "RTX 4090"}, {UUID: "GPU-y", Model: "rtx 4090"}},
+			[]string{"GPU-a <- 0 by model", "GPU-b <- 1 by model"}},
+		{"uuid entry not taken by model", []gpu.DState{a, b},
+			[]Device{{UUID: "GPU-x", Model: "RTX 4090"}, {UUID: "GPU-b", Model: "RTX 4090"}},
+			[]string{"GPU-a <- 0 by model", "GPU-b <- 1 by uuid"}},
+		{"entries used once", []gpu.DState{a, b},
+			[]Device{{UUID: "GPU-x", Model: "RTX 4090"}},
+			[]string{"GPU-a <- 0 by model"}},
+		{"other model left out", []gpu.DState{a, c},
+			[]Device{{UUID: "GPU-x", Model: "RTX 4090"}, {UUID: "GPU-y", Model: "RTX 4080"}},
+			[]string{"GPU-a <- 0 by model"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Profile{Version: FormatVersion, Devices: tt.entries}
+			var got []string
+			for _, m := range p.Remap(tt.states, config.New("")) {
+				i := -1
+				for j, e := range tt.entries {
+					if e.UUID == m.Entry.UUID {
+						i = j
+					}
+				}
+				got = append(got, fmt.Sprintf("%s <- %d by %s", m.Local.UUID, i, m.By))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Remap = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// A profile exported before settings were keyed by param ID is read in the
+// device's units, checked against the local limits and diffed against what
+// the config resolves to.
+func TestRemapLegacyProfile(t *testing.T) {
+	data := `
+version: 1
+devices:
+  - uuid: GPU-x
+    model: Fake GPU
+    settings:
+      pl: 300
+      gpu_co: 105
+      gpu_cl: 1500
+`
+	p, err := Unmarshal([]byte(data), YAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.New("")
+	cfg.Set("GPU-fake", config.GpuSettings{Params: map[string]int{"pl": 200000, "gpu_co": 105}})
+
+	ms := p.Remap([]gpu.DState{gputest.State(gputest.New())}, cfg)
+	if len(ms) != 1 {
+		t.Fatalf("%d mappings, want 1", len(ms))
+	}
+	m := ms[0]
+	if want := []string{"pl 300000 out of range [100000, 250000]"}; !reflect.DeepEqual(m.Warnings, want) {
+		t.Errorf("warnings %q, want %q", m.Warnings, want)
+	}
+	if want := []string{"pl: 200000 -> 300000", "gpu_cl: 0 -> 1500"}; !reflect.DeepEqual(m.Diff(), want) {
+		t.Errorf("diff %q, want %q", m.Diff(), want)
+	}
+}
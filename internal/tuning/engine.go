@@ -0,0 +1,215 @@
+package tuning
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"nvtuner-go/internal/gpu"
+)
+
+type Mode int
+
+const (
+	AllOrNothing Mode = iota // roll back everything on the first failure
+	BestEffort               // apply what can be applied, keep going on failure
+)
+
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "", "atomic", "all-or-nothing":
+		return AllOrNothing, nil
+	case "best-effort":
+		return BestEffort, nil
+	default:
+		return AllOrNothing, fmt.Errorf("unknown apply mode %q", s)
+	}
+}
+
+type StepResult struct {
+	ID          string
+	Previous    gpu.Value // snapshot before applying
+	Target      gpu.Value
+	Readback    gpu.Value // invalid if the driver can't read it back
+	Applied     bool
+	Skipped     bool // no-op, or not attempted
+	Err         error
+	RollbackErr error
+}
+
+type Result struct {
+	Index      int
+	Mode       Mode
+	Steps      []StepResult
+	RolledBack bool
+}
+
+// Failed lists the steps that did not end up applied as requested.
+func (r Result) Failed() []StepResult {
+	var res []StepResult
+	for _, s := range r.Steps {
+		if s.Err != nil {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func (r Result) Err() error {
+	var errs []error
+	for _, s := range r.Steps {
+		if s.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.ID, s.Err))
+		}
+		if s.RollbackErr != nil {
+			errs = append(errs, fmt.Errorf("%s rollback: %w", s.ID, s.RollbackErr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r Result) String() string {
+	applied, skipped := 0, 0
+	for _, s := range r.Steps {
+		switch {
+		case s.Applied:
+			applied++
+		case s.Skipped:
+			skipped++
+		}
+	}
+	var parts []string
+	parts = append(parts, fmt.Sprintf("%d applied", applied))
+	if skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d unchanged", skipped))
+	}
+	if n := len(r.Failed()); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", n))
+	}
+	if r.RolledBack {
+		parts = append(parts, "rolled back")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Execute applies a plan to a device: it snapshots current values, applies
+// steps in dependency order, verifies each by reading it back and, in
+// AllOrNothing mode, restores the snapshot as soon as a step fails.
+func Execute(plan Plan, dev gpu.Device, mode Mode) Result {
+	res := Result{Index: plan.Index, Mode: mode}
+	steps := orderSteps(plan.Steps)
+
+	if mode == AllOrNothing {
+		for _, s := range steps {
+			if s.WillFail && !s.NoOp {
+				for _, s2 := range steps {
+					r := StepResult{ID: s2.Param.ID, Target: s2.Target, Previous: s2.Current, Skipped: true}
+					if s2.Param.ID == s.Param.ID {
+						r.Err = errors.New(s.Reason)
+					}
+					res.Steps = append(res.Steps, r)
+				}
+				return res
+			}
+		}
+	}
+
+	for i, s := range steps {
+		r := StepResult{ID: s.Param.ID, Target: s.Target}
+		if s.Param.Get != nil {
+			r.Previous = s.Param.Unit.Read(s.Param.Get(dev))
+		}
+
+		switch {
+		case s.NoOp:
+			r.Skipped = true
+		case s.WillFail:
+			r.Skipped, r.Err = true, errors.New(s.Reason)
+		case s.Param.Set == nil:
+			r.Skipped, r.Err = true, fmt.Errorf("%w: read only", gpu.ErrNotSupported)
+		default:
+			r.Err = s.Param.Set(dev, s.Target.V) // valid, or it'd be a no-op
+			if r.Err == nil {
+				r.Applied = true
+				r.Readback, r.Err = verify(s, dev)
+			}
+		}
+		res.Steps = append(res.Steps, r)
+
+		if r.Err != nil && mode == AllOrNothing {
+			rollback(&res, steps, dev)
+			for _, rest := range steps[i+1:] {
+				res.Steps = append(res.Steps, StepResult{
+					ID: rest.Param.ID, Target: rest.Target, Skipped: true})
+			}
+			return res
+		}
+	}
+	return res
+}
+
+func verify(s Step, dev gpu.Device) (gpu.Value, error) {
+	if s.Param.Get == nil {
+		return gpu.Value{}, nil
+	}
+	v, err := s.Param.Get(dev)
+	if err != nil {
+		return s.Param.Unit.NA(err), nil // can't read back, trust the setter
+	}
+	if v != s.Target.V {
+		return s.Param.Unit.Of(v), fmt.Errorf("read back %s, want %s", s.Param.Format(v), s.Param.Format(s.Target.V))
+	}
+	return s.Param.Unit.Of(v), nil
+}
+
+// rollback restores every step touched so far, newest first.
+func rollback(res *Result, steps []Step, dev gpu.Device) {
+	res.RolledBack = true
+	for i := len(res.Steps) - 1; i >= 0; i-- {
+		r := &res.Steps[i]
+		if !r.Applied {
+			continue
+		}
+		p := steps[i].Param
+		switch {
+		case r.Previous.Valid:
+			r.RollbackErr = p.Set(dev, r.Previous.V)
+		case p.Reset != nil:
+			r.RollbackErr = p.Reset(dev) // clock locks: back to open
+		default:
+			r.RollbackErr = errors.New("previous value unknown")
+			continue
+		}
+		r.Applied = false
+	}
+}
+
+// orderSteps sorts steps so that every step comes after its DependsOn,
+// keeping the declared order otherwise.
+func orderSteps(steps []Step) []Step {
+	byID := make(map[string]int, len(steps))
+	for i, s := range steps {
+		byID[s.Param.ID] = i
+	}
+
+	res := make([]Step, 0, len(steps))
+	state := make([]int, len(steps)) // 0 new, 1 visiting, 2 done
+	var visit func(i int)
+	visit = func(i int) {
+		if state[i] != 0 {
+			return // done, or a cycle which we break here
+		}
+		state[i] = 1
+		for _, dep := range steps[i].Param.DependsOn {
+			if j, ok := byID[dep]; ok {
+				visit(j)
+			}
+		}
+		state[i] = 2
+		res = append(res, steps[i])
+	}
+	for i := range steps {
+		visit(i)
+	}
+	return res
+}
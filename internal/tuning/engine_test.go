@@ -0,0 +1,75 @@
+package tuning
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"nvtuner-go/internal/config"
+	"nvtuner-go/internal/gpu"
+	"nvtuner-go/internal/gpu/gputest"
+)
+
+func TestExecute(t *testing.T) {
+	// the fake starts at pl=200000 gpu_co=0 gpu_cl=1995 gpu_cl_min=210
+	cfg := config.GpuSettings{Params: map[string]int{"pl": 220000, "gpu_co": 105, "gpu_cl": 1500, "gpu_cl_min": 1005}}
+	errPerm := fmt.Errorf("%w: needs root", gpu.ErrNoPermission)
+	tests := []struct {
+		name       string
+		mode       Mode
+		fail       string // param whose Set fails with errPerm
+		willFail   string // param the plan says will fail
+		writes     []string
+		rolledBack bool
+	}{
+		{"all applied", AllOrNothing, "", "",
+			[]string{"pl=220000", "gpu_co=105", "gpu_cl=1500", "gpu_cl_min=1005"}, false},
+		{"rolled back newest first", AllOrNothing, "gpu_cl", "",
+			[]string{"pl=220000", "gpu_co=105", "gpu_co=0", "pl=200000"}, true},
+		{"lock restored to what it read", AllOrNothing, "gpu_cl_min", "",
+			[]string{"pl=220000", "gpu_co=105", "gpu_cl=1500", "gpu_cl=1995", "gpu_co=0", "pl=200000"}, true},
+		{"planned failure writes nothing", AllOrNothing, "", "gpu_co", nil, false},
+		{"best effort keeps going", BestEffort, "gpu_cl", "",
+			[]string{"pl=220000", "gpu_co=105", "gpu_cl_min=1005"}, false},
+		{"best effort skips planned failure", BestEffort, "", "gpu_co",
+			[]string{"pl=220000", "gpu_cl=1500", "gpu_cl_min=1005"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev := gputest.New()
+			d := gputest.State(dev)
+			plan := PlanApply(d.Params, dev, d, cfg)
+			for i, s := range plan.Steps {
+				if s.Param.ID == tt.willFail {
+					plan.Steps[i].WillFail, plan.Steps[i].Reason = true, "not writable"
+				}
+			}
+			if tt.fail != "" {
+				dev.Fail[tt.fail] = errPerm
+			}
+
+			res := Execute(plan, dev, tt.mode)
+			if !reflect.DeepEqual(dev.Writes, tt.writes) {
+				t.Errorf("writes %q, want %q", dev.Writes, tt.writes)
+			}
+			if res.RolledBack != tt.rolledBack {
+				t.Errorf("rolled back = %v, want %v", res.RolledBack, tt.rolledBack)
+			}
+			if len(res.Steps) != len(plan.Steps) {
+				t.Errorf("%d step results for %d steps", len(res.Steps), len(plan.Steps))
+			}
+			if got := errors.Is(res.Err(), gpu.ErrNoPermission); got != (tt.fail != "") {
+				t.Errorf("Err() = %v, want one wrapping ErrNoPermission: %v", res.Err(), tt.fail != "")
+			}
+			for _, s := range res.Steps {
+				if tt.rolledBack && s.Applied {
+					t.Errorf("%s still applied after the rollback", s.ID)
+				}
+				if s.RollbackErr != nil {
+					t.Errorf("%s rollback: %v", s.ID, s.RollbackErr)
+				}
+			}
+		})
+	}
+}
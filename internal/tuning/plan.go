@@ -0,0 +1,97 @@
+package tuning
+
+import (
+	"fmt"
+
+	"nvtuner-go/internal/config"
+	"nvtuner-go/internal/gpu"
+)
+
+// Step is what applying one parameter would do.
+type Step struct {
+	Param    gpu.ParamDef
+	Current  gpu.Value // as last read from hardware
+	Target   gpu.Value // invalid if it depends on something unknown
+	NoOp     bool      // hardware already has the target value
+	WillFail bool      // driver or limits say the set will not succeed
+	Reason   string    // why WillFail
+}
+
+type Plan struct {
+	Index int
+	Name  string
+	Steps []Step
+}
+
+// PlanApply plans writing the configured settings to a device.
+func PlanApply(params []gpu.ParamDef, dev gpu.Device, d gpu.DState, cfg config.GpuSettings) Plan {
+	return plan(params, dev, d, func(p gpu.ParamDef) gpu.Value { return p.Unit.Of(cfg.Get(p.ID)) })
+}
+
+// PlanReset plans restoring a device to its defaults.
+func PlanReset(params []gpu.ParamDef, dev gpu.Device, d gpu.DState) Plan {
+	return plan(params, dev, d, func(p gpu.ParamDef) gpu.Value { return p.Default })
+}
+
+func plan(params []gpu.ParamDef, dev gpu.Device, d gpu.DState, target func(gpu.ParamDef) gpu.Value) Plan {
+	caps := d.Caps
+	if caps == nil {
+		caps = dev.GetCapabilities()
+	}
+
+	// what earlier steps set, for the params depending on them
+	targets, defaults := map[string]gpu.Value{}, map[string]gpu.Value{}
+	lookup := func(m map[string]gpu.Value) func(string) gpu.Value {
+		return func(id string) gpu.Value { return m[id] }
+	}
+
+	pl := Plan{Index: d.Index, Name: d.Name}
+	for _, p := range params {
+		s := Step{Param: p, Current: p.Current(d), Target: target(p)}
+		def, asked := p.Default, s.Target
+		if p.Snap != nil {
+			if s.Target.Valid {
+				s.Target = p.Snap(d, s.Target.V, lookup(targets)) // what the driver will read back
+			}
+			if def.Valid {
+				def = p.Snap(d, def.V, lookup(defaults))
+			}
+		}
+		targets[p.ID], defaults[p.ID] = s.Target, def
+		// a target snapped to an unknown current value asks for nothing
+		s.NoOp = !s.Target.Valid || s.Current.Valid && s.Current.V == s.Target.V
+
+		if c := caps.Get(p.ID); !c.Writable() {
+			if def.Valid && s.Target.V == def.V {
+				s.NoOp = true // nothing asked of it, don't fail the plan
+			} else {
+				s.WillFail, s.Reason = true, c.Reason
+			}
+		}
+		// table clocks were snapped above, but a max lock can't be snapped
+		// up past what was asked for
+		lo, hi, ok := p.Range()
+		switch {
+		case !ok || !s.Target.Valid || s.WillFail:
+		case p.Kind == gpu.KindLockMax:
+			if asked.Valid && !p.Unset(asked.V) && asked.V < lo {
+				s.WillFail, s.Reason = true, fmt.Sprintf("below lowest clock %s", p.Format(lo))
+			}
+		case p.Snap == nil && (s.Target.V < lo || s.Target.V > hi):
+			s.WillFail, s.Reason = true, fmt.Sprintf("out of range [%s, %s]", p.Format(lo), p.Format(hi))
+		}
+		pl.Steps = append(pl.Steps, s)
+	}
+	return pl
+}
+
+// Changes counts the steps that would touch hardware.
+func (pl Plan) Changes() int {
+	n := 0
+	for _, s := range pl.Steps {
+		if !s.NoOp {
+			n++
+		}
+	}
+	return n
+}
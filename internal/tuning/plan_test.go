@@ -0,0 +1,51 @@
+package tuning
+
+import (
+	"reflect"
+	"testing"
+
+	"nvtuner-go/internal/config"
+	"nvtuner-go/internal/gpu/gputest"
+)
+
+// Application clocks are planned as the pair the driver will snap them to,
+// the graphics clock on the table of the memory clock set alongside.
+func TestPlanAppClocks(t *testing.T) {
+	// the fake runs at 1500/5001 MHz, its defaults
+	tests := []struct {
+		name    string
+		params  map[string]int
+		targets map[string]int // app_gpu and app_mem
+		changes int
+	}{
+		{"unset", nil, map[string]int{"app_gpu": 1500, "app_mem": 5001}, 0},
+		{"already set", map[string]int{"app_gpu": 1500, "app_mem": 5001}, map[string]int{"app_gpu": 1500, "app_mem": 5001}, 0},
+		{"snapped down", map[string]int{"app_gpu": 1600}, map[string]int{"app_gpu": 1500, "app_mem": 5001}, 0},
+		{"graphics clock", map[string]int{"app_gpu": 1005}, map[string]int{"app_gpu": 1005, "app_mem": 5001}, 1},
+		{"memory clock moves graphics", map[string]int{"app_mem": 405}, map[string]int{"app_gpu": 405, "app_mem": 405}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev := gputest.New()
+			d := gputest.State(dev)
+			cfg := config.GpuSettings{Params: map[string]int{"pl": 200000}}
+			for id, v := range tt.params {
+				cfg.Set(id, v)
+			}
+
+			plan := PlanApply(d.Params, dev, d, cfg)
+			targets := map[string]int{}
+			for _, s := range plan.Steps {
+				if s.Param.ID == "app_gpu" || s.Param.ID == "app_mem" {
+					targets[s.Param.ID] = s.Target.V
+				}
+			}
+			if !reflect.DeepEqual(targets, tt.targets) {
+				t.Errorf("targets %v, want %v", targets, tt.targets)
+			}
+			if n := plan.Changes(); n != tt.changes {
+				t.Errorf("%d changes, want %d", n, tt.changes)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package ui
+
+import (
+	"fmt"
+	"nvtuner-go/internal/gpu"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// clockCursor is the selected cell of the clock explorer: a memory clock
+// row and a graphics clock within it. Both index the ascending tables but
+// rows are listed fastest first.
+type clockCursor struct {
+	mem, gpu int
+}
+
+// clockMarks are the clocks the explorer highlights.
+type clockMarks struct {
+	current, appDefault, app gpu.Value // current is snapped onto the table
+	lockMin, lockMax         gpu.Value
+}
+
+func marksOf(table []int, cur, appDef, app, lockMin, lockMax gpu.Value) clockMarks {
+	if cur.Valid && len(table) > 0 {
+		cur = gpu.UnitMHz.Of(gpu.SnapDown(table, cur.V))
+	} else {
+		cur = gpu.UnitMHz.NA(nil)
+	}
+	return clockMarks{cur, appDef, app, lockMin, lockMax}
+}
+
+func (cm clockMarks) style(v int) lg.Style {
+	is := func(m gpu.Value) bool { return m.Valid && m.V == v }
+	st := lg.NewStyle().Foreground(plt.Dim)
+	if cm.lockMin.Valid && cm.lockMax.Valid && v >= cm.lockMin.V && v <= cm.lockMax.V {
+		st = st.Foreground(plt.Border)
+	}
+	if is(cm.app) {
+		st = st.Foreground(plt.Major)
+	}
+	if is(cm.appDefault) {
+		st = st.Underline(true)
+	}
+	if is(cm.current) {
+		st = st.Bold(true).Foreground(plt.Hyper)
+	}
+	return st
+}
+
+// clampClockCursor keeps the cursor inside the tables of the selected GPU.
+func (m *Model) clampClockCursor() {
+	t := m.dStates[m.selectedGpu].Clocks
+	m.clockCur.mem = max(0, min(m.clockCur.mem, len(t.Mem)-1))
+	if len(t.Mem) == 0 {
+		m.clockCur.gpu = 0
+		return
+	}
+	m.clockCur.gpu = max(0, min(m.clockCur.gpu, len(t.Gpu[t.Mem[m.clockCur.mem]])-1))
+}
+
+// clockCursorToCurrent moves the cursor onto the clocks the GPU runs at.
+func (m *Model) clockCursorToCurrent() {
+	d := m.dStates[m.selectedGpu]
+	t := d.Clocks
+	if len(t.Mem) == 0 || !d.ClockMem.Valid {
+		return
+	}
+	m.clockCur.mem, _ = slices.BinarySearch(t.Mem, gpu.SnapDown(t.Mem, d.ClockMem.V))
+	if gclks := t.Gpu[t.Mem[m.clockCur.mem]]; len(gclks) > 0 && d.ClockGpu.Valid {
+		m.clockCur.gpu, _ = slices.BinarySearch(gclks, gpu.SnapDown(gclks, d.ClockGpu.V))
+	}
+}
+
+// selectedClocks returns the memory and graphics clock under the cursor.
+func (m *Model) selectedClocks() (int, int, bool) {
+	t := m.dStates[m.selectedGpu].Clocks
+	if len(t.Mem) == 0 {
+		return 0, 0, false
+	}
+	mem := t.Mem[m.clockCur.mem]
+	gclks := t.Gpu[mem]
+	if len(gclks) == 0 {
+		return mem, 0, false
+	}
+	return mem, gclks[m.clockCur.gpu], true
+}
+
+func (m *Model) clocksView(width, height int) string {
+	d := &m.dStates[m.selectedGpu]
+	t := d.Clocks
+	cw := max(0, width-2)
+
+	if len(t.Mem) == 0 {
+		body := th.Disabled.Width(cw).Render("No clock table, see 'nvtuner caps'")
+		return RenderBoxWithTitle("CLOCKS", body)
+	}
+
+	appMem, _ := d.Param("app_mem")
+	appGpu, _ := d.Param("app_gpu")
+	memMarks := marksOf(t.Mem, d.ClockMem, appMem.Default, d.Setting("app_mem"), d.Setting("mem_cl_min"), d.Setting("mem_cl"))
+	selMem, selGpu, _ := m.selectedClocks()
+	curGpu := gpu.UnitMHz.NA(nil) // only in the row of the current memory clock
+	if memMarks.current.Valid && memMarks.current.V == selMem {
+		curGpu = d.ClockGpu
+	}
+	gpuMarks := marksOf(t.Gpu[selMem], curGpu, appGpu.Default, d.Setting("app_gpu"), d.Setting("gpu_cl_min"), d.Setting("gpu_cl"))
+
+	// left: memory clocks, fastest first
+	var memRows []string
+	memRows = append(memRows, th.PrimaryBold.Render("MEM MHz"))
+	for i := len(t.Mem) - 1; i >= 0; i-- {
+		v := t.Mem[i]
+		cursor := "  "
+		if i == m.clockCur.mem {
+			cursor = th.Focus.Render("> ")
+		}
+		n := th.Disabled.Render(fmt.Sprintf(" (%d)", len(t.Gpu[v])))
+		memRows = append(memRows, cursor+memMarks.style(v).Render(fmt.Sprintf("%5d", v))+n)
+	}
+	memView := lg.NewStyle().MarginRight(2).Render(lg.JoinVertical(lg.Left, memRows...))
+
+	// right: graphics clocks of the selected memory clock
+	const cellW = 6
+	gw := max(cellW, cw-lg.Width(memView))
+	perRow := gw / cellW
+	gclks := t.Gpu[selMem]
+	var gpuRows []string
+	gpuRows = append(gpuRows, th.PrimaryBold.Render(fmt.Sprintf("GPU MHz @ %d MHz mem", selMem)))
+	var line strings.Builder
+	for i, v := range gclks {
+		st := gpuMarks.style(v)
+		if v == selGpu {
+			st = st.Reverse(true)
+		}
+		line.WriteString(st.Render(fmt.Sprintf("%5d", v)) + " ")
+		if (i+1)%perRow == 0 || i == len(gclks)-1 {
+			gpuRows = append(gpuRows, line.String())
+			line.Reset()
+		}
+	}
+	gpuView := lg.JoinVertical(lg.Left, gpuRows...)
+
+	legend := lg.JoinHorizontal(lg.Left,
+		lg.NewStyle().Bold(true).Foreground(plt.Hyper).Render("current"), "  ",
+		lg.NewStyle().Foreground(plt.Border).Render("locked"), "  ",
+		lg.NewStyle().Foreground(plt.Major).Render("app"), "  ",
+		th.Disabled.Underline(true).Render("app default"))
+	rows := []string{lg.JoinHorizontal(lg.Top, memView, gpuView), "", legend}
+	if m.statusMsg != "" {
+		rows = append(rows, th.Focus.MaxWidth(cw).MaxHeight(1).Render(m.statusMsg))
+	}
+	body := lg.NewStyle().Width(cw).MaxHeight(max(0, height-2)).Render(lg.JoinVertical(lg.Left, rows...))
+	return RenderBoxWithTitle("CLOCKS", body)
+}
+
+// setLockFromTable stores a clock picked in the explorer as a lock bound in
+// the config of the selected GPU.
+func (m *Model) setLockFromTable(id string) {
+	mem, gclk, ok := m.selectedClocks()
+	if !ok {
+		return
+	}
+	v := gclk
+	if id == "mem_cl" {
+		v = mem
+	}
+
+	d := m.dStates[m.selectedGpu]
+	for _, p := range m.params() {
+		if p.ID != id {
+			continue
+		}
+		if c := d.Caps.Get(id); !c.Writable() {
+			m.statusIsErr, m.statusMsg = true, "Not settable: "+c.Reason
+			return
+		}
+		cfg := m.settingsOf(d)
+		cfg.Set(p.ID, v)
+		m.config.Set(d.UUID, cfg)
+		if err := m.config.Save(); err != nil {
+			m.statusIsErr, m.statusMsg = true, fmt.Sprintf("Save Failed: %v", err)
+			return
+		}
+		m.statusIsErr, m.statusMsg = false, fmt.Sprintf("%s = %d MHz saved. Press 'a' to Apply.", p.ShortLabel, v)
+		return
+	}
+}
+
+func (m *Model) updateClocks(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.statusMsg = ""
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Back), key.Matches(msg, keys.Clocks):
+			m.page = PageMain
+		case key.Matches(msg, keys.Tab):
+			m.selectedGpu = (m.selectedGpu + 1) % len(m.dStates)
+			m.clampTuningIndex()
+		case key.Matches(msg, keys.Up): // rows are listed fastest first
+			m.clockCur.mem++
+		case key.Matches(msg, keys.Down):
+			m.clockCur.mem--
+		case key.Matches(msg, keys.Left):
+			m.clockCur.gpu--
+		case key.Matches(msg, keys.Right):
+			m.clockCur.gpu++
+		case key.Matches(msg, keys.Enter):
+			m.setLockFromTable("gpu_cl")
+		case key.Matches(msg, keys.LockMin):
+			m.setLockFromTable("gpu_cl_min")
+		case key.Matches(msg, keys.LockMem):
+			m.setLockFromTable("mem_cl")
+		}
+		m.clampClockCursor()
+	case statusMsg:
+		m.statusMsg, m.statusIsErr = msg.text, msg.err
+	}
+	return m, nil
+}
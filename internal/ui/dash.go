@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"math"
 	"nvtuner-go/internal/gpu"
 	"strings"
 
@@ -47,9 +48,17 @@ func (m *Model) fillerView(width int) string {
 
 func barView(s *gpu.DState, width int, selected bool) string {
 	// data
-	memUsedG := float64(s.MemUsed) / GIGA
-	memTotalG := float64(s.MemTotal) / GIGA
-	memPct := int(memUsedG / memTotalG)
+	memOK := s.MemUsed.Valid && s.MemTotal.Valid && s.MemTotal.V > 0
+	memUsedG := float64(s.MemUsed.V) / GIGA
+	memTotalG := float64(s.MemTotal.V) / GIGA
+	var memPct gpu.Value
+	if memOK {
+		memPct = gpu.UnitPercent.Of(int(100 * memUsedG / memTotalG))
+	}
+	watts := s.Power
+	if watts.Valid {
+		watts = gpu.UnitNone.Of(int(math.Round(gpu.Watts(watts.V))))
+	}
 
 	// views
 	prefixView := fmt.Sprintf("%2d", s.Index) + "["
@@ -58,14 +67,34 @@ func barView(s *gpu.DState, width int, selected bool) string {
 		prefixView = lg.NewStyle().Foreground(plt.Hyper).Render(prefixView)
 		suffixView = lg.NewStyle().Foreground(plt.Hyper).Render(suffixView)
 	}
-	coreView := th.Value.Render(fmt.Sprintf("%3d%% %4dMHz%3d°C%4dW"+" ",
-		s.UtilGpu, s.ClockGpu, s.Temp, s.Power/1024))
+	if s.PcieBelowMax() { // link trained down, see the pcie page
+		suffixView = lg.NewStyle().Foreground(plt.Warning).Render("!")
+	}
+	valStyle := th.Value
+	switch s.Health {
+	case gpu.HealthLost:
+		text := fmt.Sprintf("%2d[ GPU lost, waiting for it to come back ]", s.Index)
+		return th.Disabled.Width(width).MaxWidth(width).MaxHeight(1).Render(text)
+	case gpu.HealthDegraded: // values are the last known ones
+		valStyle = th.Disabled.Strikethrough(true)
+		prefixView = lg.NewStyle().Foreground(plt.Warning).Render(fmt.Sprintf("%2d", s.Index) + "[")
+	}
+	coreView := valStyle.Render(fmt.Sprintf("%s%% %sMHz%s°C%sW"+" ",
+		fmtVal(s.UtilGpu, 3), fmtVal(s.ClockGpu, 4), fmtVal(s.Temp, 3), fmtVal(watts, 4)))
 
 	wMem := width - lg.Width(prefixView) - lg.Width(coreView) - lg.Width(suffixView)
-	memThin := th.Value.Render(fmt.Sprintf("M%3d%%", memPct))
-	memRglr := th.Value.Render(fmt.Sprintf("%3s/%-3sG", fm3(memUsedG), fm3(memTotalG)))
+	// codec load only where it leaves room for the memory gauge
+	if media := valStyle.Render(mediaBadge(s.Media)); wMem-lg.Width(media) >= len("99.9/99.9G ||") {
+		coreView += media
+		wMem -= lg.Width(media)
+	}
+	memThin := valStyle.Render(fmt.Sprintf("M%s%%", fmtVal(memPct, 3)))
+	memRglr := valStyle.Render(fmt.Sprintf("%3s/%-3sG", fm3(memUsedG), fm3(memTotalG)))
+	if !memOK {
+		memRglr = valStyle.Render(fmt.Sprintf("%-8s", "N/A"))
+	}
 	var memWide string
-	if lg.Width(memRglr)+lg.Width(" ||") <= wMem {
+	if memOK && lg.Width(memRglr)+lg.Width(" ||") <= wMem {
 		gaugeSize := wMem - lg.Width(memRglr) - 1
 		gaugeUsed := max(1, int(float64(gaugeSize)*memUsedG/memTotalG))
 
@@ -103,3 +132,11 @@ func fm3(f float64) string {
 	}
 	return s
 }
+
+// fmtVal right-aligns v in w columns, or N/A.
+func fmtVal(v gpu.Value, w int) string {
+	if !v.Valid {
+		return fmt.Sprintf("%*s", w, "N/A")
+	}
+	return fmt.Sprintf("%*d", w, v.V)
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"nvtuner-go/internal/gpu"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+const eventKeep = 200 // events kept for the event log
+
+type eventMsg gpu.Event
+
+func (m *Model) waitEvent() tea.Cmd {
+	if m.eventCh == nil {
+		return nil
+	}
+	ch := m.eventCh
+	return func() tea.Msg {
+		e, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return eventMsg(e)
+	}
+}
+
+func (m *Model) handleEvent(msg eventMsg) (tea.Model, tea.Cmd) {
+	e := gpu.Event(msg)
+	m.eventLog = append(m.eventLog, e)
+	m.eventLog = m.eventLog[max(0, len(m.eventLog)-eventKeep):]
+	if severe(e.Kind) {
+		m.statusIsErr, m.statusMsg = true, e.String()+", see 'v' for the event log"
+	}
+	return m, m.waitEvent()
+}
+
+// severe events mean the GPU may be misbehaving, the rest are informative.
+func severe(k gpu.EventKind) bool {
+	return k == gpu.EventXid || k == gpu.EventEccDoubleBit
+}
+
+func (m *Model) eventsView(width, height int) string {
+	cw := max(0, width-2)
+	ch := max(0, height-2)
+
+	var rows []string
+	switch {
+	case m.events == nil:
+		rows = append(rows, th.Disabled.Render("The driver can't report events"))
+	case len(m.eventLog) == 0:
+		rows = append(rows, th.Disabled.Render("No events since start"))
+	}
+	// newest first, as many as fit
+	for i := len(m.eventLog) - 1; i >= 0 && len(rows) < ch; i-- {
+		e := m.eventLog[i]
+		st := lg.NewStyle()
+		switch {
+		case severe(e.Kind):
+			st = st.Foreground(plt.Error)
+		case e.Kind == gpu.EventEccSingleBit:
+			st = st.Foreground(plt.Warning)
+		case e.Index != m.dStates[m.selectedGpu].Index:
+			st = th.Disabled
+		}
+		rows = append(rows, th.Label.Render(e.Time.Format(time.TimeOnly))+"  "+st.Render(e.String()))
+	}
+	if m.statusMsg != "" && len(rows) < ch {
+		rows = append(rows, th.Focus.MaxWidth(cw).MaxHeight(1).Render(m.statusMsg))
+	}
+
+	body := lg.NewStyle().Width(cw).Height(ch).MaxHeight(ch).Render(lg.JoinVertical(lg.Left, rows...))
+	return RenderBoxWithTitle(fmt.Sprintf("EVENTS (%d)", len(m.eventLog)), body)
+}
+
+func (m *Model) updateEvents(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.statusMsg = ""
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Back), key.Matches(msg, keys.Events):
+			m.page = PageMain
+		case key.Matches(msg, keys.Tab):
+			m.selectedGpu = (m.selectedGpu + 1) % len(m.dStates)
+			m.clampTuningIndex()
+		}
+	case statusMsg:
+		m.statusMsg, m.statusIsErr = msg.text, msg.err
+	}
+	return m, nil
+}
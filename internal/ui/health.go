@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"fmt"
+	"nvtuner-go/internal/gpu"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// healthInterval is how often the health page re-reads the counters; they
+// are not part of the sampler's telemetry.
+const healthInterval = 2 * time.Second
+
+type healthMsg struct {
+	gen    int // stale polls, from before switching GPU, stop
+	slot   int
+	report gpu.HealthReport
+	err    error
+}
+
+// fetchHealth reads the report of the selected device off the UI goroutine.
+func (m *Model) fetchHealth(delay time.Duration) tea.Cmd {
+	gen, slot := m.healthGen, m.selectedGpu
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		var r gpu.HealthReport
+		err := m.sampler.Do(slot, func(dev gpu.Device) (err error) {
+			r, err = dev.GetHealthReport()
+			return err
+		})
+		return healthMsg{gen, slot, r, err}
+	})
+}
+
+func (m *Model) handleHealth(msg healthMsg) (tea.Model, tea.Cmd) {
+	m.health[msg.slot], m.healthErr[msg.slot] = msg.report, msg.err
+	if msg.gen != m.healthGen {
+		return m, nil
+	}
+	if m.page != PageHealth {
+		m.healthPolling = false
+		return m, nil
+	}
+	return m, m.fetchHealth(healthInterval)
+}
+
+func (m *Model) healthView(width, height int) string {
+	i := m.selectedGpu
+	r := m.health[i]
+	cw := max(0, width-2)
+
+	num := func(v gpu.Value) string {
+		n, ok := v.Get()
+		if !ok {
+			return th.Disabled.Render(fmt.Sprintf("%9s", "N/A"))
+		}
+		return fmt.Sprintf("%9d", n)
+	}
+	flag := func(f gpu.Flag) string {
+		switch f {
+		case gpu.FlagOn:
+			return lg.NewStyle().Foreground(plt.Warning).Render("yes")
+		case gpu.FlagOff:
+			return "no"
+		default:
+			return th.Disabled.Render("N/A")
+		}
+	}
+	label := func(s string) string { return th.Label.Render(fmt.Sprintf("%-16s", s)) }
+
+	var rows []string
+	if err := m.healthErr[i]; err != nil {
+		rows = append(rows, lg.NewStyle().Foreground(plt.Error).Render(err.Error()))
+	}
+	rows = append(rows,
+		label("ECC:")+r.Ecc.String()+th.Disabled.Render(" (after reboot: "+r.EccPending.String()+")"),
+		label("ECC ERRORS:")+th.Disabled.Render(fmt.Sprintf("%9s %9s", "volatile", "aggregate")),
+		label("  corrected")+num(r.EccCounts.VolatileCorrected)+" "+num(r.EccCounts.AggregateCorrected),
+		label("  uncorrected")+num(r.EccCounts.VolatileUncorrected)+" "+num(r.EccCounts.AggregateUncorrected),
+		"",
+		label("RETIRED PAGES:")+th.Disabled.Render(fmt.Sprintf("%9s %9s", "sbe", "dbe")),
+		label("")+num(r.RetiredSbe)+" "+num(r.RetiredDbe)+"  pending: "+flag(r.RetiredPending),
+		label("REMAPPED ROWS:")+th.Disabled.Render(fmt.Sprintf("%9s %9s", "corr", "uncorr")),
+		label("")+num(r.RemappedCorrectable)+" "+num(r.RemappedUncorrectable)+
+			"  pending: "+flag(r.RemapPending)+"  failed: "+flag(r.RemapFailed),
+		"",
+	)
+
+	if len(r.Xids) == 0 {
+		rows = append(rows, label("XID:")+"none since start")
+	} else {
+		rows = append(rows, label("XID:"))
+		for _, x := range r.Xids[max(0, len(r.Xids)-5):] {
+			rows = append(rows, lg.NewStyle().Foreground(plt.Error).Render(
+				fmt.Sprintf("  %s  Xid %d", x.Time.Format(time.TimeOnly), x.Code)))
+		}
+	}
+	rows = append(rows, "")
+
+	if problems := r.Problems(); len(problems) == 0 {
+		rows = append(rows, lg.NewStyle().Foreground(plt.Success).Render("No memory errors need attention"))
+	} else {
+		for _, p := range problems {
+			rows = append(rows, lg.NewStyle().Foreground(plt.Warning).Render("! "+p))
+		}
+	}
+	if m.statusMsg != "" {
+		rows = append(rows, th.Focus.MaxWidth(cw).MaxHeight(1).Render(m.statusMsg))
+	}
+
+	body := lg.NewStyle().Width(cw).MaxHeight(max(0, height-2)).Render(lg.JoinVertical(lg.Left, rows...))
+	return RenderBoxWithTitle("HEALTH", body)
+}
+
+func (m *Model) updateHealth(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.statusMsg = ""
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Back), key.Matches(msg, keys.Health):
+			m.page = PageMain
+		case key.Matches(msg, keys.Tab):
+			m.selectedGpu = (m.selectedGpu + 1) % len(m.dStates)
+			m.clampTuningIndex()
+			m.healthGen++
+			return m, m.fetchHealth(0)
+		}
+	case statusMsg:
+		m.statusMsg, m.statusIsErr = msg.text, msg.err
+	}
+	return m, nil
+}
+
+// openHealth shows the health page and starts polling the counters.
+func (m *Model) openHealth() tea.Cmd {
+	m.page = PageHealth
+	if m.healthPolling {
+		return nil
+	}
+	m.healthPolling = true
+	return m.fetchHealth(0)
+}
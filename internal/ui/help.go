@@ -17,6 +17,14 @@ var keys = keyMap{
 		key.WithKeys("tab"),
 		key.WithHelp("tab", "switch GPU"),
 	),
+	Left: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←/h", "left"),
+	),
+	Right: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→/l", "right"),
+	),
 	Enter: key.NewBinding(
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "edit"),
@@ -33,6 +41,38 @@ var keys = keyMap{
 		key.WithKeys("r"),
 		key.WithHelp("r", "reset"),
 	),
+	Clocks: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "clock table"),
+	),
+	LockMin: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "lock gpu min"),
+	),
+	LockMem: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "lock mem"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+	Pcie: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "pcie"),
+	),
+	Health: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "health"),
+	),
+	Media: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "media"),
+	),
+	Events: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "events"),
+	),
 	Uuid: key.NewBinding(
 		key.WithKeys("u"),
 		key.WithHelp("u", "toggle UUID"),
@@ -44,24 +84,67 @@ var keys = keyMap{
 }
 
 type keyMap struct {
-	Up    key.Binding
-	Down  key.Binding
-	Tab   key.Binding
-	Enter key.Binding
-	Save  key.Binding
-	Apply key.Binding
-	Reset key.Binding
-	Uuid  key.Binding
-	Quit  key.Binding
+	Up      key.Binding
+	Down    key.Binding
+	Left    key.Binding
+	Right   key.Binding
+	Tab     key.Binding
+	Enter   key.Binding
+	Save    key.Binding
+	Apply   key.Binding
+	Reset   key.Binding
+	Clocks  key.Binding
+	LockMin key.Binding
+	LockMem key.Binding
+	Back    key.Binding
+	Pcie    key.Binding
+	Health  key.Binding
+	Media   key.Binding
+	Events  key.Binding
+	Uuid    key.Binding
+	Quit    key.Binding
+}
+
+func (k keyMap) steps() (key.Binding, key.Binding) {
+	left, right := k.Left, k.Right
+	left.SetHelp("←/h", "step down")
+	right.SetHelp("→/l", "step up")
+	return left, right
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Tab, k.Up, k.Down, k.Enter, k.Apply, k.Reset, k.Uuid, k.Quit}
+	left, right := k.steps()
+	return []key.Binding{k.Tab, k.Up, k.Down, left, right, k.Enter, k.Apply, k.Reset, k.Clocks, k.Pcie, k.Media, k.Health, k.Events, k.Uuid, k.Quit}
 }
 
 func (k keyMap) FullHelp() [][]key.Binding {
+	left, right := k.steps()
 	return [][]key.Binding{
-		{k.Tab, k.Up, k.Down, k.Enter},
-		{k.Apply, k.Reset, k.Uuid, k.Quit},
+		{k.Tab, k.Up, k.Down, left, right, k.Enter},
+		{k.Apply, k.Reset, k.Clocks, k.Pcie, k.Media, k.Health, k.Events, k.Uuid, k.Quit},
 	}
 }
+
+// clockKeyMap is the help shown on the clock explorer page.
+type clockKeyMap struct{ keyMap }
+
+func (k clockKeyMap) ShortHelp() []key.Binding {
+	enter := k.Enter
+	enter.SetHelp("enter", "lock gpu max")
+	return []key.Binding{k.Tab, k.Up, k.Down, k.Left, k.Right, enter, k.LockMin, k.LockMem, k.Back, k.Quit}
+}
+
+func (k clockKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// pageKeyMap is the help shown on read-only pages.
+type pageKeyMap struct{ keyMap }
+
+func (k pageKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Tab, k.Back, k.Quit}
+}
+
+func (k pageKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"fmt"
+	"nvtuner-go/internal/gpu"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// sessionsInterval is how often the media page re-reads the encoder
+// sessions; the utilization comes with the sampler's telemetry.
+const sessionsInterval = 2 * time.Second
+
+type mediaMsg struct {
+	gen      int // see healthMsg
+	slot     int
+	sessions []gpu.EncoderSession
+	err      error
+}
+
+func (m *Model) fetchSessions(delay time.Duration) tea.Cmd {
+	gen, slot := m.mediaGen, m.selectedGpu
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		var s []gpu.EncoderSession
+		err := m.sampler.Do(slot, func(dev gpu.Device) (err error) {
+			s, err = dev.GetEncoderSessions()
+			return err
+		})
+		return mediaMsg{gen, slot, s, err}
+	})
+}
+
+func (m *Model) handleMedia(msg mediaMsg) (tea.Model, tea.Cmd) {
+	m.sessions[msg.slot], m.sessionsErr[msg.slot] = msg.sessions, msg.err
+	if msg.gen != m.mediaGen {
+		return m, nil
+	}
+	if m.page != PageMedia {
+		m.mediaPolling = false
+		return m, nil
+	}
+	return m, m.fetchSessions(sessionsInterval)
+}
+
+// mediaBadge is the encoder and decoder load for the dashboard, or "" if
+// the device has no codecs.
+func mediaBadge(md gpu.MediaStats) string {
+	if !md.HasCodec() {
+		return ""
+	}
+	pct := func(v gpu.Value) string {
+		n, ok := v.Get()
+		if !ok {
+			return " --"
+		}
+		return fmt.Sprintf("%3d", n)
+	}
+	return fmt.Sprintf("E%s%% D%s%% ", pct(md.UtilEnc), pct(md.UtilDec))
+}
+
+func (m *Model) mediaView(width, height int) string {
+	i := m.selectedGpu
+	md := m.dStates[i].Media
+	cw := max(0, width-2)
+
+	num := func(v gpu.Value) string {
+		n, ok := v.Get()
+		if !ok {
+			return th.Disabled.Render("N/A")
+		}
+		return fmt.Sprintf("%d%s", n, v.Unit)
+	}
+	label := func(s string) string { return th.Label.Render(fmt.Sprintf("%-10s", s)) }
+
+	rows := []string{
+		label("ENCODER:") + num(md.UtilEnc) + th.Disabled.Render(" sessions ") + num(md.EncSessions) +
+			th.Disabled.Render(" fps ") + num(md.EncFps) + th.Disabled.Render(" latency ") + num(md.EncLatency),
+		label("DECODER:") + num(md.UtilDec),
+		label("JPEG:") + num(md.UtilJpeg),
+		label("OFA:") + num(md.UtilOfa),
+		label("FBC:") + th.Disabled.Render("sessions ") + num(md.FbcSessions) +
+			th.Disabled.Render(" fps ") + num(md.FbcFps) + th.Disabled.Render(" latency ") + num(md.FbcLatency),
+		"",
+	}
+	switch sessions, err := m.sessions[i], m.sessionsErr[i]; {
+	case err != nil:
+		rows = append(rows, th.Disabled.Render("Encoder sessions: "+err.Error()))
+	case len(sessions) == 0:
+		rows = append(rows, th.Disabled.Render("No encoder sessions"))
+	default:
+		rows = append(rows, th.Label.Render(fmt.Sprintf("%8s %-6s %11s %5s %9s", "PID", "CODEC", "RESOLUTION", "FPS", "LATENCY")))
+		for _, s := range sessions {
+			rows = append(rows, fmt.Sprintf("%8d %-6s %11s %5d %7dus",
+				s.Pid, s.Codec, fmt.Sprintf("%dx%d", s.Width, s.Height), s.Fps, s.Latency))
+		}
+	}
+	if m.statusMsg != "" {
+		rows = append(rows, th.Focus.MaxWidth(cw).MaxHeight(1).Render(m.statusMsg))
+	}
+	info := RenderBoxWithTitle("MEDIA", lg.NewStyle().Width(cw).Render(lg.JoinVertical(lg.Left, rows...)))
+
+	chartH := height - lg.Height(info)
+	if chartH < 6 || !md.HasCodec() {
+		return info
+	}
+	wEnc := cw / 2
+	charts := lg.JoinHorizontal(lg.Top,
+		m.tsView(wEnc, chartH, "Encoder (%)", m.encHistory[i], 0, 100),
+		m.tsView(cw-wEnc, chartH, "Decoder (%)", m.decHistory[i], 0, 100))
+	return lg.JoinVertical(lg.Left, info, charts)
+}
+
+func (m *Model) updateMedia(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.statusMsg = ""
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Back), key.Matches(msg, keys.Media):
+			m.page = PageMain
+		case key.Matches(msg, keys.Tab):
+			m.selectedGpu = (m.selectedGpu + 1) % len(m.dStates)
+			m.clampTuningIndex()
+			m.mediaGen++
+			return m, m.fetchSessions(0)
+		}
+	case statusMsg:
+		m.statusMsg, m.statusIsErr = msg.text, msg.err
+	}
+	return m, nil
+}
+
+// openMedia shows the media page and starts polling the encoder sessions.
+func (m *Model) openMedia() tea.Cmd {
+	m.page = PageMedia
+	if m.mediaPolling {
+		return nil
+	}
+	m.mediaPolling = true
+	return m.fetchSessions(0)
+}
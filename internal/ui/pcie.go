@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"nvtuner-go/internal/gpu"
+	tinyrb "nvtuner-go/internal/utils"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// pcieBadge warns about a link running below its maximum under load, or
+// returns "".
+func pcieBadge(d gpu.DState) string {
+	if !d.PcieBelowMax() {
+		return ""
+	}
+	return lg.NewStyle().Foreground(plt.Warning).Render(" PCIe " + d.PcieLink.String())
+}
+
+func (m *Model) pcieView(width, height int) string {
+	d := m.dStates[m.selectedGpu]
+	cw := max(0, width-2)
+
+	link := d.PcieLink.String()
+	if d.PcieBelowMax() {
+		link = lg.NewStyle().Foreground(plt.Warning).Render(link + " (below max under load)")
+	}
+	rate := func(kbps gpu.Value) string {
+		if !kbps.Valid {
+			return th.Disabled.Render("N/A")
+		}
+		return fmt.Sprintf("%.1f MB/s", float64(kbps.V)/1024)
+	}
+	replays := th.Disabled.Render("N/A")
+	if d.PcieReplays.Valid {
+		replays = fmt.Sprintf("%d", d.PcieReplays.V)
+	}
+
+	lines := [][2]string{
+		{"BUS ID:", d.Pci.BusID},
+		{"LINK:", link},
+		{"MAX LINK:", d.PcieMax.String()},
+		{"TX:", rate(d.PcieTx)},
+		{"RX:", rate(d.PcieRx)},
+		{"REPLAYS:", replays},
+	}
+	var rows []string
+	for _, l := range lines {
+		rows = append(rows, th.Label.Render(fmt.Sprintf("%-10s", l[0]))+" "+l[1])
+	}
+	if m.statusMsg != "" {
+		rows = append(rows, th.Focus.MaxWidth(cw).MaxHeight(1).Render(m.statusMsg))
+	}
+	info := RenderBoxWithTitle("PCIE", lg.NewStyle().Width(cw).Render(lg.JoinVertical(lg.Left, rows...)))
+
+	chartH := height - lg.Height(info)
+	if chartH < 6 {
+		return info
+	}
+	// MB/s; a Gen4 x16 link moves about 32 GB/s each way
+	top := 1.0
+	for _, rb := range []*tinyrb.RingBuffer[DataPoint]{m.pcieTxHistory[m.selectedGpu], m.pcieRxHistory[m.selectedGpu]} {
+		for _, p := range rb.Get() {
+			top = max(top, p.Value*1.2)
+		}
+	}
+	wTx := cw / 2
+	charts := lg.JoinHorizontal(lg.Top,
+		m.tsView(wTx, chartH, "PCIe TX (MB/s)", m.pcieTxHistory[m.selectedGpu], 0, top),
+		m.tsView(cw-wTx, chartH, "PCIe RX (MB/s)", m.pcieRxHistory[m.selectedGpu], 0, top))
+	return lg.JoinVertical(lg.Left, info, charts)
+}
+
+func (m *Model) updatePcie(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.statusMsg = ""
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Back), key.Matches(msg, keys.Pcie):
+			m.page = PageMain
+		case key.Matches(msg, keys.Tab):
+			m.selectedGpu = (m.selectedGpu + 1) % len(m.dStates)
+			m.clampTuningIndex()
+		}
+	case statusMsg:
+		m.statusMsg, m.statusIsErr = msg.text, msg.err
+	}
+	return m, nil
+}
@@ -2,7 +2,9 @@ package ui
 
 import (
 	"fmt"
+	"nvtuner-go/internal/config"
 	"nvtuner-go/internal/gpu"
+	"nvtuner-go/internal/tuning"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,6 +21,47 @@ const (
 
 type PopupState struct {
 	Type PopupType
+	Plan *tuning.Plan // nil until planned off the UI goroutine
+}
+
+type planMsg struct {
+	typ  PopupType
+	plan tuning.Plan
+}
+
+type applyDoneMsg struct {
+	typ  PopupType
+	slot int
+	ds   gpu.DState
+	cfg  config.GpuSettings
+	res  tuning.Result
+}
+
+// openPopup shows a popup and plans its action in the background, since
+// planning reads the hardware.
+func (m *Model) openPopup(pt PopupType) tea.Cmd {
+	slot, ds := m.selectedGpu, m.dStates[m.selectedGpu]
+	if ds.Health == gpu.HealthLost {
+		m.statusIsErr, m.statusMsg = true, "GPU is lost"
+		return nil
+	}
+	m.popup = PopupState{Type: pt}
+	params, cfg := m.params(), m.settingsOf(ds)
+	return func() tea.Msg {
+		var plan tuning.Plan
+		err := m.sampler.Do(slot, func(dev gpu.Device) error {
+			if pt == PopupReset {
+				plan = tuning.PlanReset(params, dev, ds)
+			} else {
+				plan = tuning.PlanApply(params, dev, ds, cfg)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil // quitting
+		}
+		return planMsg{pt, plan}
+	}
 }
 
 func (m *Model) popupView(width, height int) string {
@@ -37,69 +80,113 @@ func (m *Model) popupView(width, height int) string {
 
 	if pt == PopupApply {
 		title = "APPLY SETTINGS"
-		cfg, _ := m.config.Get(d.UUID)
-		body.WriteString(nameStyle.Render(fmt.Sprintf("%s (ID: %d)", d.Name, d.Index)) + "\n")
-		for _, p := range m.tuningParams {
-			val := "  N/A"
-			if v := p.GetConfig(cfg); v != gpu.NO_VALUE {
-				val = fmt.Sprintf("%5d", v)
-			}
-			body.WriteString(lg.JoinHorizontal(lg.Left, keyStyle.Render(p.ShortLabel),
-				th.Focus.Render(val), th.Value.Render(fmt.Sprintf(" %-5s", p.Unit))) + "\n")
-		}
 		desc = "Apply this profile?"
 	} else {
 		title = "RESET TO DEFAULT"
-		body.WriteString(nameStyle.Render(fmt.Sprintf("GPU %d: %s", d.Index, d.Name)) + "\n")
-		for _, p := range m.tuningParams {
-			val := "  N/A"
-			if v := p.GetDefault(d); v != gpu.NO_VALUE {
-				val = fmt.Sprintf("%5d", v)
-			}
-			body.WriteString(lg.JoinHorizontal(lg.Left, keyStyle.Render(p.ShortLabel),
-				th.Focus.Render(val), th.Value.Render(fmt.Sprintf(" %-5s", p.Unit))) + "\n")
-		}
 		desc = "Restore to preset defaults?"
 	}
 
+	body.WriteString(nameStyle.Render(fmt.Sprintf("GPU %d: %s", d.Index, d.Name)) + "\n")
+	if plan := m.popup.Plan; plan == nil {
+		body.WriteString(th.Disabled.Render("Reading hardware...") + "\n")
+	} else {
+		for _, st := range plan.Steps {
+			body.WriteString(planStepView(st, keyStyle) + "\n")
+		}
+		if plan.Changes() == 0 {
+			desc = "Nothing to change. " + desc
+		}
+	}
+
 	text := body.String() + descStyle.Render(desc) + "\n\n" + th.Value.Render("(Enter to Confirm / q to Cancel)")
 	box := RenderBoxWithTitle(title, lg.NewStyle().Align(lg.Center).Padding(1, 2).Render(text))
 	return lg.Place(width, height, lg.Center, lg.Center, box,
 		lg.WithWhitespaceChars(" "), lg.WithWhitespaceForeground(plt.Dim))
 }
 
-func (m *Model) handlePopupConfirm() (tea.Model, tea.Cmd) {
-	pt, ds := m.popup.Type, m.dStates[m.selectedGpu]
-	m.popup.Type = PopupNone
+// planStepView renders "LABEL  current -> target unit  note".
+func planStepView(st tuning.Step, keyStyle lg.Style) string {
+	fmtVal := func(v gpu.Value) string { return fmt.Sprintf("%5s", st.Param.FormatValue(v)) }
+
+	valStyle, note := th.Focus, ""
+	switch {
+	case st.WillFail:
+		valStyle = lg.NewStyle().Foreground(plt.Warning)
+		note = st.Reason
+	case st.NoOp:
+		valStyle = th.Disabled
+		note = "no change"
+	}
 
-	errs := make(map[string][]string)
-	cfg, _ := m.config.Get(ds.UUID)
+	return lg.JoinHorizontal(lg.Left,
+		keyStyle.Render(st.Param.ShortLabel),
+		th.Value.Render(fmtVal(st.Current)),
+		th.Disabled.Render(" -> "),
+		valStyle.Render(fmtVal(st.Target)),
+		th.Value.Render(fmt.Sprintf(" %-4s", st.Param.DisplayUnit())),
+		th.Disabled.Width(24).Render(note))
+}
 
-	for _, p := range m.tuningParams {
-		val := p.GetConfig(cfg)
-		if pt == PopupReset {
-			val = p.GetDefault(ds)
-			p.SetConfig(&cfg, val)
-		}
-		if err := p.Apply(m.devices[m.selectedGpu], val); err != nil {
-			msg := err.Error()
-			errs[msg] = append(errs[msg], p.ShortLabel)
+func (m *Model) handlePopupConfirm() (tea.Model, tea.Cmd) {
+	if m.popup.Plan == nil {
+		return m, nil // still planning
+	}
+	pt, plan, slot := m.popup.Type, *m.popup.Plan, m.selectedGpu
+	ds := m.dStates[slot]
+	cfg := m.settingsOf(ds)
+	m.popup = PopupState{Type: PopupNone}
+
+	mode, err := tuning.ParseMode(m.config.ApplyMode)
+	if err != nil {
+		m.statusIsErr, m.statusMsg = true, err.Error()
+		return m, nil
+	}
+
+	m.statusIsErr, m.statusMsg = false, "Applying..."
+	return m, func() tea.Msg {
+		var res tuning.Result
+		err := m.sampler.Do(slot, func(dev gpu.Device) error {
+			res = tuning.Execute(plan, dev, mode)
+			return nil
+		})
+		if err != nil {
+			return nil // quitting
 		}
+		return applyDoneMsg{typ: pt, slot: slot, ds: ds, cfg: cfg, res: res}
 	}
+}
 
-	if pt == PopupReset {
+func (m *Model) handleApplyDone(msg applyDoneMsg) (tea.Model, tea.Cmd) {
+	res, cfg, ds := msg.res, msg.cfg, msg.ds
+	m.sampler.Refresh(msg.slot) // limits and defaults may have moved
+
+	if msg.typ == PopupReset && !res.RolledBack {
+		for _, p := range ds.Params {
+			if v, ok := p.Default.Get(); ok {
+				cfg.Set(p.ID, v)
+			}
+		}
 		m.config.Set(ds.UUID, cfg)
 		m.config.Save()
 	}
 
-	if len(errs) == 0 {
-		m.statusIsErr, m.statusMsg = false, "Action completed successfully"
+	failed := res.Failed()
+	if len(failed) == 0 {
+		m.statusIsErr, m.statusMsg = false, "Done: "+res.String()
 		return m, nil
 	}
 
-	var msgs []string
-	for msg, labels := range errs {
-		msgs = append(msgs, fmt.Sprintf("[%s]: %s", strings.Join(labels, ","), msg))
+	errs := make(map[string][]string)
+	for _, r := range failed {
+		msg := r.Err.Error()
+		if hint := gpu.Hint(r.Err); hint != "" {
+			msg += " (" + hint + ")"
+		}
+		errs[msg] = append(errs[msg], r.ID)
+	}
+	msgs := []string{res.String()}
+	for msg, ids := range errs {
+		msgs = append(msgs, fmt.Sprintf("[%s]: %s", strings.Join(ids, ","), msg))
 	}
 	m.statusIsErr, m.statusMsg = true, strings.Join(msgs, " | ")
 	return m, nil
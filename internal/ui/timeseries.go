@@ -5,10 +5,26 @@ import (
 	tinyrb "nvtuner-go/internal/utils"
 	"time"
 
+	"github.com/NimbleMarkets/ntcharts/canvas"
 	tslc "github.com/NimbleMarkets/ntcharts/linechart/timeserieslinechart"
+	lg "github.com/charmbracelet/lipgloss"
 )
 
-func (m *Model) tsView(width, height int, title string, data *tinyrb.RingBuffer[DataPoint], limMin, limMax float64) string {
+// hline is a horizontal marker drawn across a chart, like a threshold.
+type hline struct {
+	Value float64
+	Style lg.Style
+}
+
+func (m *Model) tsView(width, height int, title string, data *tinyrb.RingBuffer[DataPoint], limMin, limMax float64, lines ...hline) string {
+	return m.tsViewOver(width, height, title, data, nil, limMin, limMax, lines...)
+}
+
+// overSet names the second series of a chart.
+const overSet = "over"
+
+// tsViewOver is tsView with a second series, over, drawn in another color.
+func (m *Model) tsViewOver(width, height int, title string, data, over *tinyrb.RingBuffer[DataPoint], limMin, limMax float64, lines ...hline) string {
 	if width <= 10 || height <= 5 {
 		return ""
 	}
@@ -22,6 +38,12 @@ func (m *Model) tsView(width, height int, title string, data *tinyrb.RingBuffer[
 			Value: p.Value,
 		})
 	}
+	if over != nil {
+		c.SetDataSetStyle(overSet, lg.NewStyle().Foreground(plt.Major))
+		for _, p := range over.Get() {
+			c.PushDataSet(overSet, tslc.TimePoint{Time: p.Time, Value: p.Value})
+		}
+	}
 
 	c.AxisStyle = th.Disabled
 	c.LabelStyle = th.Disabled
@@ -51,6 +73,14 @@ func (m *Model) tsView(width, height int, title string, data *tinyrb.RingBuffer[
 	c.SetStyle(th.Focus)
 	c.DrawXYAxisAndLabel()
 	c.DrawBrailleAll()
+	for _, l := range lines {
+		if l.Value <= limMin || l.Value >= limMax {
+			continue
+		}
+		c.DrawRuneLineWithStyle(
+			canvas.Float64Point{X: c.ViewMinX(), Y: l.Value},
+			canvas.Float64Point{X: c.ViewMaxX(), Y: l.Value}, '┄', l.Style)
+	}
 
 	return RenderBoxWithTitle(title, c.View())
 }
@@ -1,8 +1,8 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
 	"time"
 
 	"nvtuner-go/internal/config"
@@ -18,6 +18,11 @@ import (
 
 const GIGA = 1024 * 1024 * 1024
 
+const (
+	pollInterval = time.Second / 2
+	pollTimeout  = 2 * time.Second
+)
+
 var _ tea.Model = (*Model)(nil)
 
 type Model struct {
@@ -26,29 +31,64 @@ type Model struct {
 	config  *config.Manager
 	driver  gpu.Manager
 	mState  gpu.MState
-	devices []gpu.Device
 	dStates []gpu.DState
+	sampler *gpu.Sampler
 
 	selectedGpu int
 	showUuid    bool
+	page        Page
+	clockCur    clockCursor
 
-	tuningIndex  int
-	isEditing    bool
-	tuningParams []TuningParam
-	tuningInput  textinput.Model
+	tuningIndex int
+	isEditing   bool
+	tuningInput textinput.Model
 
 	statusMsg   string
 	statusIsErr bool
 
-	clockHistory []*tinyrb.RingBuffer[DataPoint]
-	powerHistory []*tinyrb.RingBuffer[DataPoint]
-	tempHistory  []*tinyrb.RingBuffer[DataPoint]
-	memHistory   []*tinyrb.RingBuffer[DataPoint]
+	clockHistory  []*tinyrb.RingBuffer[DataPoint]
+	powerHistory  []*tinyrb.RingBuffer[DataPoint]
+	tempHistory   []*tinyrb.RingBuffer[DataPoint]
+	memTHistory   []*tinyrb.RingBuffer[DataPoint] // memory temperature, drawn over tempHistory
+	memHistory    []*tinyrb.RingBuffer[DataPoint]
+	utilHistory   []*tinyrb.RingBuffer[DataPoint]
+	utilMHistory  []*tinyrb.RingBuffer[DataPoint] // memory utilization, drawn over utilHistory
+	pcieTxHistory []*tinyrb.RingBuffer[DataPoint]
+	pcieRxHistory []*tinyrb.RingBuffer[DataPoint]
+	encHistory    []*tinyrb.RingBuffer[DataPoint]
+	decHistory    []*tinyrb.RingBuffer[DataPoint]
+
+	health        []gpu.HealthReport // read while the health page is open
+	healthErr     []error
+	healthPolling bool
+	healthGen     int
+
+	sessions     [][]gpu.EncoderSession // read while the media page is open
+	sessionsErr  []error
+	mediaPolling bool
+	mediaGen     int
+
+	events   *gpu.Listener // nil if the driver has no events
+	eventCh  <-chan gpu.Event
+	eventLog []gpu.Event
 
 	help  help.Model
 	popup PopupState
 }
-type tickMsg time.Time
+
+// Page is what takes the place of the tuning panel and charts.
+type Page int
+
+const (
+	PageMain   Page = iota
+	PageClocks      // supported clocks explorer
+	PagePcie        // link details and throughput
+	PageMedia       // encoder, decoder and their sessions
+	PageHealth      // memory errors and Xids
+	PageEvents      // what the driver reported on its own
+)
+
+type snapshotMsg gpu.Snapshot
 type statusMsg struct { // TODO: why do we need this?
 	text string
 	err  bool
@@ -72,28 +112,29 @@ func New(drv gpu.Manager, cfg *config.Manager) (*Model, error) {
 		dStates[i].FetchOnce(d)
 	}
 
-	// load / create configs
-	for _, d := range devs {
-		uuid := d.GetUUID()
-		if _, ok := cfg.Get(uuid); !ok {
-			defPl, _ := d.GetPlDefault()
-			_, defCl, _ := d.GetClLimGpu()
-			initSetting := config.GpuSettings{
-				PowerLimit: defPl,
-				GpuCO:      0,
-				MemCO:      0,
-				GpuCL:      defCl,
+	// load / create configs; devices covered by a rule keep using it
+	for _, ds := range dStates {
+		if _, _, ok := cfg.Resolve(config.IdentityOf(ds)); !ok {
+			var initSetting config.GpuSettings
+			for _, p := range ds.Params {
+				if v, ok := p.Default.Get(); ok {
+					initSetting.Set(p.ID, v)
+				}
 			}
-			cfg.Set(uuid, initSetting)
+			cfg.Set(ds.UUID, initSetting)
 		}
 	}
 	cfg.Save()
 
+	var status string
+	if n := countErrors(cfg.Check(dStates, false)); n > 0 {
+		status = fmt.Sprintf("Config has %d error(s), see 'nvtuner config check'", n)
+	}
+
 	// init tuning panel
-	params := getDefaultTuningParams()
 	ti := textinput.New()
 	ti.Prompt = ""
-	ti.CharLimit = 5
+	ti.CharLimit = 7 // "450.125" W
 	ti.Width = 5
 	ti.TextStyle = th.Focus
 
@@ -101,28 +142,67 @@ func New(drv gpu.Manager, cfg *config.Manager) (*Model, error) {
 	histClock := make([]*tinyrb.RingBuffer[DataPoint], len(devs))
 	histPower := make([]*tinyrb.RingBuffer[DataPoint], len(devs))
 	histTemp := make([]*tinyrb.RingBuffer[DataPoint], len(devs))
+	histMemT := make([]*tinyrb.RingBuffer[DataPoint], len(devs))
 	histMem := make([]*tinyrb.RingBuffer[DataPoint], len(devs))
+	histUtil := make([]*tinyrb.RingBuffer[DataPoint], len(devs))
+	histUtilM := make([]*tinyrb.RingBuffer[DataPoint], len(devs))
+	histTx := make([]*tinyrb.RingBuffer[DataPoint], len(devs))
+	histRx := make([]*tinyrb.RingBuffer[DataPoint], len(devs))
+	histEnc := make([]*tinyrb.RingBuffer[DataPoint], len(devs))
+	histDec := make([]*tinyrb.RingBuffer[DataPoint], len(devs))
 	for i := range devs {
-		histClock[i] = tinyrb.New[DataPoint](512)
-		histPower[i] = tinyrb.New[DataPoint](512)
+		histClock[i] = tinyrb.New[DataPoint](2048) // driver samples come faster than ticks
+		histPower[i] = tinyrb.New[DataPoint](2048)
 		histTemp[i] = tinyrb.New[DataPoint](512)
+		histMemT[i] = tinyrb.New[DataPoint](512)
 		histMem[i] = tinyrb.New[DataPoint](512)
+		histUtil[i] = tinyrb.New[DataPoint](2048)
+		histUtilM[i] = tinyrb.New[DataPoint](2048)
+		histTx[i] = tinyrb.New[DataPoint](512)
+		histRx[i] = tinyrb.New[DataPoint](512)
+		histEnc[i] = tinyrb.New[DataPoint](2048)
+		histDec[i] = tinyrb.New[DataPoint](2048)
+	}
+
+	// events are optional, the sampler still shows their effects
+	var eventCh <-chan gpu.Event
+	events, err := gpu.NewListener(drv)
+	if err == nil {
+		eventCh, _ = events.Subscribe(64) // closed by events.Stop
 	}
 
 	return &Model{
 		config:  cfg,
 		driver:  drv,
 		mState:  mState,
-		devices: devs,
 		dStates: dStates,
+		sampler: gpu.NewSampler(drv, devs, dStates, pollInterval, pollTimeout),
 
-		tuningParams: params,
-		tuningInput:  ti,
+		tuningInput: ti,
 
-		clockHistory: histClock,
-		powerHistory: histPower,
-		tempHistory:  histTemp,
-		memHistory:   histMem,
+		statusMsg:   status,
+		statusIsErr: status != "",
+
+		clockHistory:  histClock,
+		powerHistory:  histPower,
+		tempHistory:   histTemp,
+		memTHistory:   histMemT,
+		memHistory:    histMem,
+		utilHistory:   histUtil,
+		utilMHistory:  histUtilM,
+		pcieTxHistory: histTx,
+		pcieRxHistory: histRx,
+		encHistory:    histEnc,
+		decHistory:    histDec,
+
+		health:    make([]gpu.HealthReport, len(devs)),
+		healthErr: make([]error, len(devs)),
+
+		sessions:    make([][]gpu.EncoderSession, len(devs)),
+		sessionsErr: make([]error, len(devs)),
+
+		events:  events,
+		eventCh: eventCh,
 
 		help:  help.New(),
 		popup: PopupState{Type: PopupNone},
@@ -131,23 +211,83 @@ func New(drv gpu.Manager, cfg *config.Manager) (*Model, error) {
 
 func (m *Model) Init() tea.Cmd {
 	return tea.Batch(
-		doTick(),
+		m.waitSnapshot(),
+		m.waitEvent(),
 		textinput.Blink,
 	)
 }
 
+// params are the tuning parameters of the selected device. P-state offsets
+// differ between devices.
+func (m *Model) params() []gpu.ParamDef {
+	return m.dStates[m.selectedGpu].Params
+}
+
+// selectedParam is the param under the tuning cursor. There is none if the
+// device has no params, e.g. before its info was read.
+func (m *Model) selectedParam() (gpu.ParamDef, bool) {
+	ps := m.params()
+	if m.tuningIndex < 0 || m.tuningIndex >= len(ps) {
+		return gpu.ParamDef{}, false
+	}
+	return ps[m.tuningIndex], true
+}
+
+// clampTuningIndex keeps the cursor on the list after it changed, like when
+// switching GPUs or a refresh dropped P-states.
+func (m *Model) clampTuningIndex() {
+	m.tuningIndex = max(0, min(m.tuningIndex, len(m.params())-1))
+}
+
+// Close stops background polling. Call it before shutting the driver down.
+func (m *Model) Close() {
+	m.sampler.Stop()
+	if m.events != nil {
+		m.events.Stop()
+	}
+}
+
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	// tick
-	if _, ok := msg.(tickMsg); ok {
-		now := time.Now()
-		for i, d := range m.devices {
-			m.dStates[i].FetchOnce(d)
-			m.clockHistory[i].Push(DataPoint{Time: now, Value: float64(m.dStates[i].ClockGpu)})
-			m.powerHistory[i].Push(DataPoint{Time: now, Value: float64(m.dStates[i].Power)})
-			m.tempHistory[i].Push(DataPoint{Time: now, Value: float64(m.dStates[i].Temp)})
-			m.memHistory[i].Push(DataPoint{Time: now, Value: float64(m.dStates[i].MemUsed) / GIGA})
+	// telemetry
+	if msg, ok := msg.(snapshotMsg); ok {
+		i := msg.Slot
+		m.dStates[i] = msg.State
+		if i == m.selectedGpu {
+			m.clampTuningIndex()
+		}
+		if msg.State.Health != gpu.HealthLost && !errors.Is(msg.Err, gpu.ErrSampleTimeout) {
+			t, snap := msg.Time, gpu.Snapshot(msg)
+			pushSeries(m.clockHistory[i], snap, gpu.SampleClockGpu, msg.State.ClockGpu, 1)
+			pushSeries(m.powerHistory[i], snap, gpu.SamplePower, msg.State.Power, 1000)
+			pushSeries(m.utilHistory[i], snap, gpu.SampleUtilGpu, msg.State.UtilGpu, 1)
+			pushSeries(m.utilMHistory[i], snap, gpu.SampleUtilMem, msg.State.UtilMem, 1)
+			pushPoint(m.tempHistory[i], t, msg.State.Temp, 1)
+			pushPoint(m.memTHistory[i], t, msg.State.TempMem, 1)
+			pushPoint(m.memHistory[i], t, msg.State.MemUsed, GIGA)
+			pushPoint(m.pcieTxHistory[i], t, msg.State.PcieTx, 1024)
+			pushPoint(m.pcieRxHistory[i], t, msg.State.PcieRx, 1024)
+			pushSeries(m.encHistory[i], snap, gpu.SampleUtilEnc, msg.State.Media.UtilEnc, 1)
+			pushSeries(m.decHistory[i], snap, gpu.SampleUtilDec, msg.State.Media.UtilDec, 1)
 		}
-		return m, doTick()
+		return m, m.waitSnapshot()
+	}
+	if msg, ok := msg.(planMsg); ok {
+		if m.popup.Type == msg.typ {
+			m.popup.Plan = &msg.plan
+		}
+		return m, nil
+	}
+	if msg, ok := msg.(applyDoneMsg); ok {
+		return m.handleApplyDone(msg)
+	}
+	if msg, ok := msg.(healthMsg); ok {
+		return m.handleHealth(msg)
+	}
+	if msg, ok := msg.(mediaMsg); ok {
+		return m.handleMedia(msg)
+	}
+	if msg, ok := msg.(eventMsg); ok {
+		return m.handleEvent(msg)
 	}
 
 	// resize
@@ -185,8 +325,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.statusIsErr = false
 				return m, nil
 			case tea.KeyEnter: // save tuning profiles to config
-				valStr := m.tuningInput.Value()
-				val, err := strconv.Atoi(valStr)
+				param, ok := m.selectedParam()
+				if !ok {
+					m.isEditing = false
+					m.tuningInput.Blur()
+					m.statusIsErr, m.statusMsg = true, "Parameter went away"
+					return m, nil
+				}
+				val, err := param.Parse(m.tuningInput.Value())
 				if err != nil {
 					m.isEditing = false
 					m.tuningInput.Blur()
@@ -195,17 +341,20 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				d := &m.dStates[m.selectedGpu]
-				cfg, _ := m.config.Get(d.UUID)
-				param := m.tuningParams[m.tuningIndex]
-				minVal, maxVal := param.GetLimits(*d)
-				if val < minVal || val > maxVal {
+				cfg := m.settingsOf(*d)
+				lo, hi, bounded := param.Range()
+				inRange := !bounded || val >= lo && val <= hi
+				if param.Snap != nil {
+					val, inRange = param.Snap(*d, val, cfg.With(d.DInfo)).Get()
+				}
+				if !inRange {
 					m.isEditing = false
 					m.tuningInput.Blur()
 					m.statusIsErr, m.statusMsg = true, "Value out of range"
 					return m, nil
 				}
 
-				param.SetConfig(&cfg, val)
+				cfg.Set(param.ID, val)
 				m.config.Set(d.UUID, cfg)
 
 				if err := m.config.Save(); err != nil {
@@ -223,6 +372,19 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	switch m.page {
+	case PageClocks:
+		return m.updateClocks(msg)
+	case PagePcie:
+		return m.updatePcie(msg)
+	case PageMedia:
+		return m.updateMedia(msg)
+	case PageHealth:
+		return m.updateHealth(msg)
+	case PageEvents:
+		return m.updateEvents(msg)
+	}
+
 	// navigation
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -231,23 +393,49 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, keys.Quit):
 			return m, tea.Quit
 		case key.Matches(msg, keys.Tab):
-			m.selectedGpu = (m.selectedGpu + 1) % len(m.devices)
+			m.selectedGpu = (m.selectedGpu + 1) % len(m.dStates)
+			m.clampTuningIndex()
 		case key.Matches(msg, keys.Uuid):
 			m.showUuid = !m.showUuid
 		case key.Matches(msg, keys.Up):
 			m.tuningIndex = max(0, m.tuningIndex-1)
 		case key.Matches(msg, keys.Down):
-			m.tuningIndex = min(len(m.tuningParams)-1, m.tuningIndex+1)
+			m.tuningIndex = min(len(m.params())-1, m.tuningIndex+1)
+			m.clampTuningIndex()
 		case key.Matches(msg, keys.Enter):
+			d := m.dStates[m.selectedGpu]
+			p, ok := m.selectedParam()
+			if !ok {
+				return m, nil
+			}
+			if c := d.Caps.Get(p.ID); !c.Writable() {
+				m.statusIsErr, m.statusMsg = true, "Not settable: "+c.Reason
+				return m, nil
+			}
 			m.isEditing = true
-			cfg, _ := m.config.Get(m.dStates[m.selectedGpu].UUID)
-			val := m.tuningParams[m.tuningIndex].GetConfig(cfg)
-			m.tuningInput.SetValue(strconv.Itoa(val))
+			cfg := m.settingsOf(d)
+			m.tuningInput.SetValue(p.Format(cfg.Get(p.ID)))
 			m.tuningInput.Focus()
+		case key.Matches(msg, keys.Left):
+			m.stepParam(-1)
+		case key.Matches(msg, keys.Right):
+			m.stepParam(1)
 		case key.Matches(msg, keys.Apply):
-			m.popup.Type = PopupApply
+			return m, m.openPopup(PopupApply)
 		case key.Matches(msg, keys.Reset):
-			m.popup.Type = PopupReset
+			return m, m.openPopup(PopupReset)
+		case key.Matches(msg, keys.Clocks):
+			m.page = PageClocks
+			m.clockCursorToCurrent()
+			m.clampClockCursor()
+		case key.Matches(msg, keys.Pcie):
+			m.page = PagePcie
+		case key.Matches(msg, keys.Media):
+			return m, m.openMedia()
+		case key.Matches(msg, keys.Health):
+			return m, m.openHealth()
+		case key.Matches(msg, keys.Events):
+			m.page = PageEvents
 		}
 	case statusMsg:
 		m.statusMsg, m.statusIsErr = msg.text, msg.err
@@ -259,7 +447,7 @@ func (m *Model) View() string {
 	if m.width == 0 || m.height == 0 {
 		return ""
 	}
-	if len(m.devices) == 0 {
+	if len(m.dStates) == 0 {
 		return "GPU not found"
 	}
 
@@ -274,6 +462,12 @@ func (m *Model) View() string {
 
 	m.help.Width = m.width
 	helpView := m.help.View(keys)
+	switch m.page {
+	case PageClocks:
+		helpView = m.help.View(clockKeyMap{keys})
+	case PagePcie, PageMedia, PageHealth, PageEvents:
+		helpView = m.help.View(pageKeyMap{keys})
+	}
 	helpHeight := lg.Height(helpView)
 
 	boxWidth := m.width
@@ -293,24 +487,46 @@ func (m *Model) View() string {
 	ds := m.dStates[m.selectedGpu]
 	chartH := 10
 	type chartMeta struct {
-		name string
-		data *tinyrb.RingBuffer[DataPoint]
-		max  float64
+		name  string
+		data  *tinyrb.RingBuffer[DataPoint]
+		over  *tinyrb.RingBuffer[DataPoint] // second series, may be nil
+		max   float64
+		lines []hline
+	}
+	tempLines, tempMax := thermalLines(ds)
+	tempDef := chartMeta{"Temp (°C)", m.tempHistory[m.selectedGpu], nil, tempMax, tempLines}
+	if ds.TempMem.Valid {
+		tempDef.name, tempDef.over = "GPU / Mem Temp (°C)", m.memTHistory[m.selectedGpu]
 	}
+	pl, _ := ds.Param("pl")
+	gpuCl, _ := ds.Param("gpu_cl")
 	chartDefs := []chartMeta{
-		{"Temp (°C)", m.tempHistory[m.selectedGpu], 100.0},
-		{"Power (W)", m.powerHistory[m.selectedGpu], float64(ds.Limits.PlMax)},
-		{"Clock (MHz)", m.clockHistory[m.selectedGpu], float64(ds.Limits.ClGpuMax)},
-		{"Mem (MB)", m.memHistory[m.selectedGpu], float64(ds.MemTotal) / 1024 / 1024},
+		tempDef,
+		{"Power (W)", m.powerHistory[m.selectedGpu], nil, chartMax(pl.Max, 1000, m.powerHistory[m.selectedGpu]), nil},
+		{"Clock (MHz)", m.clockHistory[m.selectedGpu], nil, chartMax(gpuCl.Max, 1, m.clockHistory[m.selectedGpu]), nil},
+		{"GPU / Mem Util (%)", m.utilHistory[m.selectedGpu], m.utilMHistory[m.selectedGpu], 100, nil},
+		{"Mem (MB)", m.memHistory[m.selectedGpu], nil, chartMax(ds.MemTotal, 1024*1024, m.memHistory[m.selectedGpu]), nil},
 	}
 	const (
 		IDX_TEMP  = 0
 		IDX_POWER = 1
 		IDX_CLOCK = 2
-		IDX_MEM   = 3
+		IDX_UTIL  = 3
+		IDX_MEM   = 4
 	)
 
-	if cw <= THIN { // simply place everything in one column
+	switch {
+	case m.page == PageClocks:
+		content = lg.JoinVertical(lg.Center, content, m.clocksView(cw, hRemain))
+	case m.page == PagePcie:
+		content = lg.JoinVertical(lg.Center, content, m.pcieView(cw, hRemain))
+	case m.page == PageMedia:
+		content = lg.JoinVertical(lg.Center, content, m.mediaView(cw, hRemain))
+	case m.page == PageHealth:
+		content = lg.JoinVertical(lg.Center, content, m.healthView(cw, hRemain))
+	case m.page == PageEvents:
+		content = lg.JoinVertical(lg.Center, content, m.eventsView(cw, hRemain))
+	case cw <= THIN: // simply place everything in one column
 		chartH := 8
 		tunView := m.tuningView(cw)
 		if hRemain >= lg.Height(tunView) {
@@ -319,16 +535,16 @@ func (m *Model) View() string {
 		}
 		for _, c := range chartDefs {
 			if hRemain >= chartH {
-				v := m.tsView(cw, chartH, c.name, c.data, 0, c.max)
+				v := m.tsViewOver(cw, chartH, c.name, c.data, c.over, 0, c.max, c.lines...)
 				content = lg.JoinVertical(lg.Center, content, v)
 				hRemain -= chartH
 			} else {
 				break
 			}
 		}
-	} else {
+	default:
 		// [TUNING] [    TEMP    ]
-		// [POWER] [CLOCK] [ MEM ]
+		// [POWER] [CLOCK] [UTIL] [MEM]
 
 		wTun := int(float64(cw) * 0.4)
 		wTemp := cw - wTun
@@ -338,7 +554,7 @@ func (m *Model) View() string {
 
 		if hRemain >= row1H {
 			c := chartDefs[IDX_TEMP]
-			tempView := m.tsView(wTemp, row1H, c.name, c.data, 0, c.max)
+			tempView := m.tsViewOver(wTemp, row1H, c.name, c.data, c.over, 0, c.max, c.lines...)
 
 			row1 := lg.JoinHorizontal(lg.Top, tunView, tempView)
 			content = lg.JoinVertical(lg.Center, content, row1)
@@ -346,8 +562,8 @@ func (m *Model) View() string {
 		}
 
 		if hRemain >= chartH {
-			wCol := cw / 3
-			wColLast := cw - (wCol * 2)
+			wCol := cw / 4
+			wColLast := cw - (wCol * 3)
 
 			bottomRowCharts := []struct {
 				def   chartMeta
@@ -355,12 +571,13 @@ func (m *Model) View() string {
 			}{
 				{chartDefs[IDX_POWER], wCol},
 				{chartDefs[IDX_CLOCK], wCol},
+				{chartDefs[IDX_UTIL], wCol},
 				{chartDefs[IDX_MEM], wColLast},
 			}
 
 			var views []string
 			for _, item := range bottomRowCharts {
-				v := m.tsView(item.width, hRemain, item.def.name, item.def.data, 0, item.def.max)
+				v := m.tsViewOver(item.width, hRemain, item.def.name, item.def.data, item.def.over, 0, item.def.max)
 				views = append(views, v)
 			}
 
@@ -380,8 +597,136 @@ func (m *Model) View() string {
 	)
 }
 
-func doTick() tea.Cmd {
-	return tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+// stepParam moves the configured value of the selected param by its Step,
+// within the device's limits, and saves it.
+func (m *Model) stepParam(dir int) {
+	d := m.dStates[m.selectedGpu]
+	p, ok := m.selectedParam()
+	if !ok {
+		return
+	}
+	if c := d.Caps.Get(p.ID); !c.Writable() {
+		m.statusIsErr, m.statusMsg = true, "Not settable: "+c.Reason
+		return
+	}
+	if p.Step <= 0 {
+		return
+	}
+	cfg := m.settingsOf(d)
+	v := cfg.Get(p.ID) + dir*p.Step
+	if lo, hi, ok := p.Range(); ok {
+		v = min(max(v, lo), hi)
+	}
+	if p.Snap != nil {
+		if s, ok := p.Snap(d, v, cfg.With(d.DInfo)).Get(); ok {
+			v = s
+		}
+	}
+	cfg.Set(p.ID, v)
+	m.config.Set(d.UUID, cfg)
+	if err := m.config.Save(); err != nil {
+		m.statusIsErr, m.statusMsg = true, fmt.Sprintf("Save Failed: %v", err)
+		return
+	}
+	m.statusIsErr, m.statusMsg = false, fmt.Sprintf("%s = %s %s saved. Press 'a' to Apply.", p.ShortLabel, p.Format(v), p.DisplayUnit())
+}
+
+// thermalLines returns the temperature thresholds to mark on the chart and
+// a chart maximum that fits them.
+func thermalLines(d gpu.DState) ([]hline, float64) {
+	marks := []struct {
+		v     gpu.Value
+		color lg.TerminalColor
+	}{
+		{d.Setting("temp_target"), plt.Success},
+		{d.Thermal.GpuMax, plt.Hyper},
+		{d.Thermal.Slowdown, plt.Warning},
+		{d.Thermal.Shutdown, plt.Error},
+	}
+	if d.TempMem.Valid { // only next to the memory temperature it applies to
+		marks = append(marks, struct {
+			v     gpu.Value
+			color lg.TerminalColor
+		}{d.Thermal.MemMax, plt.Major})
+	}
+	var lines []hline
+	top := 100
+	for _, mk := range marks {
+		v, ok := mk.v.Get()
+		if !ok || v <= 0 {
+			continue
+		}
+		lines = append(lines, hline{float64(v), lg.NewStyle().Foreground(mk.color)})
+		top = max(top, v+5)
+	}
+	return lines, float64(top)
+}
+
+// settingsOf returns the configured settings for a device, whether they come
+// from its UUID entry or a matching rule.
+func (m *Model) settingsOf(d gpu.DState) config.GpuSettings {
+	s, _, _ := m.config.Resolve(config.IdentityOf(d))
+	return s
+}
+
+func countErrors(issues []config.Issue) int {
+	n := 0
+	for _, i := range issues {
+		if i.Severity == config.SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// pushPoint appends v divided by div. Invalid values leave a gap rather
+// than a point.
+func pushPoint(rb *tinyrb.RingBuffer[DataPoint], t time.Time, v gpu.Value, div float64) {
+	if f, ok := v.Float(); ok {
+		rb.Push(DataPoint{Time: t, Value: f / div})
+	}
+}
+
+// pushSeries appends the driver's samples of kind if the device buffers it,
+// else the polled value. Polled points would land between samples that
+// arrive late, so the two are never mixed. Both are divided by div, samples
+// come in the units of their Value.
+func pushSeries(rb *tinyrb.RingBuffer[DataPoint], snap gpu.Snapshot, kind gpu.SampleKind, v gpu.Value, div float64) {
+	if !snap.Sampled[kind] {
+		pushPoint(rb, snap.Time, v, div)
+		return
+	}
+	var last time.Time
+	if pts := rb.Get(); len(pts) > 0 {
+		last = pts[len(pts)-1].Time
+	}
+	for _, s := range snap.Samples[kind] {
+		if s.Time.After(last) {
+			rb.Push(DataPoint{Time: s.Time, Value: s.Value / div})
+			last = s.Time
+		}
+	}
+}
+
+// chartMax is the chart top for limit v divided by div, or a bit over the
+// largest point when the device doesn't report the limit.
+func chartMax(v gpu.Value, div float64, rb *tinyrb.RingBuffer[DataPoint]) float64 {
+	if f, ok := v.Float(); ok && f > 0 {
+		return f / div
+	}
+	top := 1.0
+	for _, p := range rb.Get() {
+		top = max(top, p.Value*1.1)
+	}
+	return top
+}
+
+func (m *Model) waitSnapshot() tea.Cmd {
+	return func() tea.Msg {
+		snap, ok := <-m.sampler.C()
+		if !ok {
+			return nil
+		}
+		return snapshotMsg(snap)
+	}
 }